@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/markdown"
+	"github.com/rdark/standupnotes/internal/util"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportDate     string
+	exportNoteType string
+)
+
+var exportStandupCmd = &cobra.Command{
+	Use:   "export-standup",
+	Short: "Export the standup for a given day",
+	Long: `Export the whole standup note for a given day in a format ready to paste into chat
+If a standup note does not exist for the given day, the standup directory will
+be searched backwards for the newest standup within 30 days of the given date
+
+The standup is exported as Slack mrkdwn unless another --format is given, or
+as the parsed note with --output json or yaml. Links to other notes, including
+wiki links such as [[2024-12-11|Yesterday]], are reduced to their text`,
+	Run: exportStandupCmdFunc,
+}
+
+var exportNoteCmd = &cobra.Command{
+	Use:   "export-note",
+	Short: "Export the note of any registered type for a given day",
+	Long: `Export the whole note of the type given with --type for a given day in a format
+ready to paste into chat
+If a note does not exist for the given day, the directory of the note type will
+be searched backwards for the newest note within 30 days of the given date
+
+The note is exported as Slack mrkdwn unless another --format is given, or as
+the parsed note with --output json or yaml`,
+	Run: exportNoteCmdFunc,
+}
+
+func init() {
+	exportStandupCmd.PersistentFlags().StringVarP(&exportDate, "date", "d", time.Now().Format("2006-01-02"), "Date to export the standup for")
+	rootCmd.AddCommand(exportStandupCmd)
+
+	exportNoteCmd.PersistentFlags().StringVarP(&exportDate, "date", "d", time.Now().Format("2006-01-02"), "Date to export the note for")
+	exportNoteCmd.PersistentFlags().StringVarP(&exportNoteType, "type", "t", "", "type of the note to export")
+	cobra.CheckErr(exportNoteCmd.MarkPersistentFlagRequired("type"))
+	rootCmd.AddCommand(exportNoteCmd)
+}
+
+func exportStandupCmdFunc(cmd *cobra.Command, args []string) {
+	standup, err := findNoteType(string(markdown.NoteTypeStandup))
+	cobra.CheckErr(err)
+	cobra.CheckErr(exportNote(standup, exportDate))
+}
+
+func exportNoteCmdFunc(cmd *cobra.Command, args []string) {
+	noteType, err := findNoteType(exportNoteType)
+	cobra.CheckErr(err)
+	cobra.CheckErr(exportNote(noteType, exportDate))
+}
+
+// exportNote prints the most recent note of noteType on or before date in the
+// configured output format
+func exportNote(noteType *noteTypeConfig, date string) error {
+	renderer, err := newOutputRenderer(markdown.FormatSlack)
+	if err != nil {
+		return err
+	}
+
+	dt, err := parseLocalDate(date)
+	if err != nil {
+		return err
+	}
+
+	mostRecent, err := util.GetMostRecentMdFileName(noteType.Dir, noteType.FilenameFormat, dt)
+	if err != nil {
+		return err
+	}
+	if mostRecent == "" {
+		return fmt.Errorf("no %s found within 30 days of %s", noteType.Type, date)
+	}
+
+	content, err := os.ReadFile(path.Join(noteType.Dir, mostRecent))
+	if err != nil {
+		return err
+	}
+
+	parser := newParser(markdown.WithRenderer(renderer))
+	md, err := parser.ParseNoteContent(string(content), noteType.SkipText, noteType.Type, markdown.WithNoteName(mostRecent))
+	if err != nil {
+		return err
+	}
+
+	if structuredOutput() {
+		printOutput(newNoteOutput(noteType, mostRecent, md), nil)
+		return nil
+	}
+	return parser.RenderNote(os.Stdout, md, noteType.SkipText)
+}
@@ -1,25 +1,41 @@
 package cmd
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
-	"strings"
-	"time"
 	"os"
+	"path/filepath"
 	"slices"
+	"strings"
+	"time"
 
-	"github.com/rdark/standupnotes/internal/util"
 	"github.com/rdark/standupnotes/internal/markdown"
+	notetemplate "github.com/rdark/standupnotes/internal/template"
+	"github.com/rdark/standupnotes/internal/util"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	createStandupCmd string
-	createJournalCmd string
+	createStandupCmd    string
+	createJournalCmd    string
+	standupTemplatePath string
+	journalTemplatePath string
+	noteTemplatePath    string
+	noteTypeName        string
+	journalRollover     bool
 )
 
 func init() {
+	generateNoteCmd.PersistentFlags().StringVarP(&noteTypeName, "type", "t", "", "type of the note to generate")
+	generateNoteCmd.PersistentFlags().StringVar(&noteTemplatePath, "template", "", "template used to render the note (default is the template configured for the note type)")
+	cobra.CheckErr(generateNoteCmd.MarkPersistentFlagRequired("type"))
+	rootCmd.AddCommand(generateNoteCmd)
+	generateStandupCmd.PersistentFlags().StringVar(&standupTemplatePath, "template", "", "template used to render the standup note (default is the built-in template)")
+	generateJournalCmd.PersistentFlags().StringVar(&journalTemplatePath, "template", "", "template used to render the journal note (default is the built-in template)")
+	generateJournalCmd.PersistentFlags().BoolVar(&journalRollover, "rollover", false, "roll open tasks of the previous journal over into the new journal")
 	rootCmd.AddCommand(generateStandupCmd)
 	rootCmd.AddCommand(generateJournalCmd)
 }
@@ -27,87 +43,458 @@ func init() {
 var generateStandupCmd = &cobra.Command{
 	Use:   "generate-standup",
 	Short: "Generate standup note and copy work done from the previous days journal into it",
-	Long: `Generate the standup note for today using the configured external program
-and copy the work done from the previous day's journal into it`,
+	Long: `Generate the standup note for today and copy the work done from the previous
+day's journal into it
+
+If an external program is configured with standup.create.cmd it is used to create
+the note, otherwise the note is rendered from the template configured with
+standup.template, or the built-in standup template`,
 	Run: generateStandupCmdFunc,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		rootCmd.PersistentPreRun(cmd, args)
-		if createStandupCmd == "" {
-			createStandupCmd = viper.GetString("standup.create.cmd")
-		}
+		loadGenerateStandupConfig()
 	},
 }
 
-func generateStandupCmdFunc(cmd *cobra.Command, args []string) {
+// loadGenerateStandupConfig reads the configuration used to create standup
+// notes unless given by flags
+func loadGenerateStandupConfig() {
+	if createStandupCmd == "" {
+		createStandupCmd = viper.GetString("standup.create.cmd")
+	}
+	if standupTemplatePath == "" {
+		standupTemplatePath = viper.GetString("standup.template")
+	}
+}
 
+func generateStandupCmdFunc(cmd *cobra.Command, args []string) {
 	if len(createStandupCmd) == 0 {
-		err := fmt.Errorf("No command configured to create standup notes")
+		standupNote, err := generateStandupNote(time.Now())
 		cobra.CheckErr(err)
+
+		printGenerated(generatedOutput{Path: standupNote})
+		return
 	}
 	createCmd := strings.Split(createStandupCmd, " ")
 
 	standupNote, err := util.ExecReturnStdOut(createCmd)
 	cobra.CheckErr(err)
 
-	fmt.Println(standupNote)
+	printGenerated(generatedOutput{Path: standupNote})
+}
+
+// generatedOutput is a generated note printed with --output
+type generatedOutput struct {
+	// Path of the note
+	Path string `json:"path" yaml:"path"`
+	// FixedLinks are the links to adjacent notes that were fixed
+	FixedLinks []markdown.AdjacentLink `json:"fixed_links,omitempty" yaml:"fixed_links,omitempty"`
+	// RolledOver are the tasks rolled over from the previous note
+	RolledOver []markdown.Task `json:"rolled_over,omitempty" yaml:"rolled_over,omitempty"`
+}
+
+// printGenerated prints the path of the generated note, along with the links
+// fixed and tasks rolled over within it
+func printGenerated(out generatedOutput) {
+	printOutput(out, func() {
+		fmt.Println(out.Path)
+		if len(out.FixedLinks) > 0 {
+			fmt.Println("Fixing links")
+			for _, link := range out.FixedLinks {
+				fmt.Printf("Fixing link: %s\n", link.Title)
+			}
+		}
+		printRolledOver(out.RolledOver)
+	})
+}
+
+// generateStandupNote renders the standup note for dt into the standup
+// directory and returns the path of the new note
+func generateStandupNote(dt time.Time) (string, error) {
+	standup, err := findNoteType(string(markdown.NoteTypeStandup))
+	if err != nil {
+		return "", err
+	}
+	data, err := newTemplateData(dt, standup)
+	if err != nil {
+		return "", err
+	}
+
+	data.Sections = map[string]notetemplate.Section{
+		"work_done": {Title: standupWorkDoneSection},
+		"today":     {Title: standupTodaySection},
+	}
+
+	if data.Journal.Previous != "" {
+		workDone, err := journalSectionsContent(data.Journal.Previous+".md", journalWorkDoneSections)
+		if err != nil {
+			return "", err
+		}
+		data.Sections["work_done"] = notetemplate.Section{Title: standupWorkDoneSection, Content: workDone}
+	}
+
+	// today's journal may not have been created yet
+	today, err := journalSectionsContent(journalFilenameFormat.Format(dt), journalGoalsSections)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	data.Sections["today"] = notetemplate.Section{Title: standupTodaySection, Content: today}
+
+	return renderNote(standup, standupTemplatePath, data)
+}
+
+// generateJournalNote renders the journal note for dt into the journal
+// directory and returns the path of the new note
+func generateJournalNote(dt time.Time) (string, error) {
+	journal, err := findNoteType(string(markdown.NoteTypeJournal))
+	if err != nil {
+		return "", err
+	}
+	return generateNote(journal, dt, journalTemplatePath)
+}
+
+// generateNote renders the note of noteType for dt from the template at
+// templatePath, carrying over every section of the previous note of the same
+// type, and returns the path of the new note
+func generateNote(noteType *noteTypeConfig, dt time.Time, templatePath string) (string, error) {
+	data, err := newTemplateData(dt, noteType)
+	if err != nil {
+		return "", err
+	}
+
+	data.Sections = make(map[string]notetemplate.Section)
+	if data.Previous != "" {
+		content, err := os.ReadFile(filepath.Join(noteType.Dir, data.Previous+".md"))
+		if err != nil {
+			return "", err
+		}
+
+		parser := newParser()
+		md, err := parser.ParseNoteContent(string(content), noteType.SkipText, noteType.Type, markdown.WithNoteName(data.Previous+".md"))
+		if err != nil {
+			return "", err
+		}
+
+		for _, section := range md.Sections {
+			data.Sections[section.Title] = notetemplate.Section{
+				Title:   section.Title,
+				Content: strings.TrimSpace(section.Content),
+			}
+		}
+	}
+
+	return renderNote(noteType, templatePath, data)
+}
+
+// newTemplateData returns the template data for the note of noteType dated dt
+func newTemplateData(dt time.Time, noteType *noteTypeConfig) (notetemplate.Data, error) {
+	previousDt := dt.AddDate(0, 0, -1)
+	notePath := filepath.Join(noteType.Dir, noteType.FilenameFormat.Format(dt))
+
+	data := notetemplate.Data{
+		Type:     string(noteType.Type),
+		Date:     dt,
+		Name:     strings.TrimSuffix(noteType.FilenameFormat.Format(dt), ".md"),
+		Next:     strings.TrimSuffix(noteType.FilenameFormat.Format(dt.AddDate(0, 0, 1)), ".md"),
+		Notes:    make(map[string]notetemplate.Adjacent),
+		Headings: noteType.Sections,
+	}
+	if len(noteType.LinkPreviousTitles) > 0 {
+		data.PreviousTitle = noteType.LinkPreviousTitles[0]
+	}
+	if len(noteType.LinkNextTitles) > 0 {
+		data.NextTitle = noteType.LinkNextTitles[0]
+	}
+
+	for _, adjacentType := range noteTypes {
+		previous, err := util.GetMostRecentMdFileName(adjacentType.Dir, adjacentType.FilenameFormat, previousDt)
+		if err != nil {
+			return data, err
+		}
+
+		// links are relative to the directory the note is written to
+		adjacent, err := notetemplate.NewAdjacent(filepath.Dir(notePath), adjacentType.Dir)
+		if err != nil {
+			return data, err
+		}
+		adjacent.Previous = strings.TrimSuffix(previous, ".md")
+		adjacent.Today = strings.TrimSuffix(adjacentType.FilenameFormat.Format(dt), ".md")
+		adjacent.Next = strings.TrimSuffix(adjacentType.FilenameFormat.Format(dt.AddDate(0, 0, 1)), ".md")
+		data.Notes[string(adjacentType.Type)] = adjacent
+	}
+
+	data.Journal = data.Notes[string(markdown.NoteTypeJournal)]
+	data.Standup = data.Notes[string(markdown.NoteTypeStandup)]
+	data.Previous = data.Notes[data.Type].Previous
+
+	var err error
+	data.FrontMatter, err = noteFrontMatter(noteType.Dir, data.Previous)
+	if err != nil {
+		return data, err
+	}
+
+	return data, nil
+}
 
+// noteFrontMatter returns the front matter of the named note in noteDir
+func noteFrontMatter(noteDir string, name string) (map[string]interface{}, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(noteDir, name+".md"))
+	if err != nil {
+		return nil, err
+	}
+
+	return markdown.ParseFrontMatter(string(content))
+}
+
+// renderNote renders the template at templatePath, or the built-in template
+// for noteType, with data into the directory of noteType and returns the path
+// of the new note. Existing notes are never overwritten.
+func renderNote(noteType *noteTypeConfig, templatePath string, data notetemplate.Data) (string, error) {
+	notePath := filepath.Join(noteType.Dir, noteType.FilenameFormat.Format(data.Date))
+	if _, err := os.Stat(notePath); err == nil {
+		return "", fmt.Errorf("%s note already exists: %s", noteType.Type, notePath)
+	}
+
+	tmpl, err := notetemplate.New(string(noteType.Type), templatePath)
+	if err != nil {
+		return "", err
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+
+	if err := noteWriter.WriteFile(notePath, out.Bytes(), 0644); err != nil {
+		return "", err
+	}
+
+	return notePath, nil
+}
+
+// journalSectionsContent returns the combined content of the named sections
+// within the given journal note
+func journalSectionsContent(journalName string, sectionTitles []string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(journalDir, journalName))
+	if err != nil {
+		return "", err
+	}
+
+	parser := newParser()
+	md, err := parser.ParseNoteContent(string(content), journalSkipText, markdown.NoteTypeJournal, markdown.WithNoteName(journalName))
+	if err != nil {
+		return "", err
+	}
+
+	var contents []string
+	for _, section := range md.Sections {
+		for _, title := range sectionTitles {
+			if strings.EqualFold(section.Title, title) && strings.TrimSpace(section.Content) != "" {
+				contents = append(contents, strings.TrimSpace(section.Content))
+			}
+		}
+	}
+
+	return strings.Join(contents, "\n"), nil
 }
 
 var generateJournalCmd = &cobra.Command{
 	Use:   "generate-journal",
 	Short: "Generate journal note and copy work done from the previous days journal into it",
-	Long: `Generate the journal note for today using the configured external program
-and copy the work done from the previous day's journal into it`,
+	Long: `Generate the journal note for today and copy the work done from the previous
+day's journal into it
+
+If an external program is configured with journal.create.cmd it is used to create
+the note, otherwise the note is rendered from the template configured with
+journal.template, or the built-in journal template`,
 	Run: generateJournalCmdFunc,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		rootCmd.PersistentPreRun(cmd, args)
-		if createJournalCmd == "" {
-			createJournalCmd = viper.GetString("journal.create.cmd")
-		}
+		loadGenerateJournalConfig()
 	},
 }
 
-func generateJournalCmdFunc(cmd *cobra.Command, args []string) {
-	if len(createJournalCmd) == 0 {
-		err := fmt.Errorf("No command configured to create journal notes")
+// loadGenerateJournalConfig reads the configuration used to create journal
+// notes unless given by flags
+func loadGenerateJournalConfig() {
+	if createJournalCmd == "" {
+		createJournalCmd = viper.GetString("journal.create.cmd")
+	}
+	if journalTemplatePath == "" {
+		journalTemplatePath = viper.GetString("journal.template")
+	}
+	if !journalRollover {
+		journalRollover = viper.GetBool("journal.rollover")
+	}
+}
+
+var generateNoteCmd = &cobra.Command{
+	Use:   "generate-note",
+	Short: "Generate a note of any registered type",
+	Long: `Generate the note for today of the type given with --type, copying each section
+of the previous note of the same type into it
+
+Note types other than journal and standup are configured under note_types, e.g.
+
+  note_types:
+    retro:
+      dir: notes/retro
+      filename_format: "%Y-%m-%d"
+      sections: ["Went well", "To improve", "Actions"]
+
+The note is rendered from the template given with --template, the template
+configured for the note type, or a built-in template listing its sections.
+Journal and standup notes are generated as with generate-journal and
+generate-standup`,
+	Run: generateNoteCmdFunc,
+}
+
+func generateNoteCmdFunc(cmd *cobra.Command, args []string) {
+	noteType, err := findNoteType(noteTypeName)
+	cobra.CheckErr(err)
+
+	switch noteType.Type {
+	case markdown.NoteTypeJournal:
+		journalTemplatePath = noteTemplatePath
+		loadGenerateJournalConfig()
+		generateJournalCmdFunc(cmd, args)
+	case markdown.NoteTypeStandup:
+		standupTemplatePath = noteTemplatePath
+		loadGenerateStandupConfig()
+		generateStandupCmdFunc(cmd, args)
+	default:
+		templatePath := noteTemplatePath
+		if templatePath == "" {
+			templatePath = noteType.Template
+		}
+		notePath, err := generateNote(noteType, time.Now(), templatePath)
 		cobra.CheckErr(err)
+		printGenerated(generatedOutput{Path: notePath})
 	}
-	createCmd := strings.Split(createJournalCmd, " ")
+}
 
+func generateJournalCmdFunc(cmd *cobra.Command, args []string) {
 	now := time.Now()
 
 	previousDt := now.AddDate(0, 0, -1)
 
-	previousJournalName, err := util.GetMostRecentMdFileName(journalDir, previousDt)
+	previousJournalName, err := util.GetMostRecentMdFileName(journalDir, journalFilenameFormat, previousDt)
 	cobra.CheckErr(err)
 
-	journalPath, err := util.ExecReturnStdOut(createCmd)
+	var journalPath string
+	if len(createJournalCmd) == 0 {
+		journalPath, err = generateJournalNote(now)
+	} else {
+		journalPath, err = util.ExecReturnStdOut(strings.Split(createJournalCmd, " "))
+	}
 	cobra.CheckErr(err)
+	out := generatedOutput{Path: journalPath}
 
-	content, err := os.ReadFile(journalPath)
+	journalName, err := filepath.Rel(journalDir, journalPath)
 	cobra.CheckErr(err)
+	journalName = filepath.ToSlash(journalName)
 
-	parser := markdown.NewParser()
-	md, err := parser.ParseNoteContent(string(content), journalSkipText, markdown.NoteTypeJournal)
+	content, err := noteWriter.ReadFile(journalPath)
 	cobra.CheckErr(err)
+	original := content
 
-	var fixableLinks []markdown.AdjacentLink
-	for _, link := range md.AdjacentLinks {
-		if link.TargetNoteType == markdown.NoteTypeJournal {
-			if slices.Contains(journalLinkPreviousTitles, link.Title) && link.Target != previousJournalName {
-				fixableLinks = append(fixableLinks, link)
-			}
-		}
+	if previousJournalName != "" {
+		content, err = carryOverSections(content, journalName, previousJournalName)
+		cobra.CheckErr(err)
 	}
 
-	if len(fixableLinks) > 0 {
-		fmt.Println("Fixing links")
-		for _, link := range fixableLinks {
-			fmt.Printf("Fixing link: %s\n", link.Title)
-			content = slices.Replace(content, link.Target, previousJournalName)
-		}
-		err = os.WriteFile(journalPath, content, 0644)
+	if previousJournalName != "" {
+		journal, err := findNoteType(string(markdown.NoteTypeJournal))
+		cobra.CheckErr(err)
+
+		content, out.FixedLinks, err = fixAdjacentLinks(journal, journalName, content, previousJournalName, "")
+		cobra.CheckErr(err)
+	}
+
+	if !bytes.Equal(content, original) {
+		err = noteWriter.WriteFile(journalPath, content, 0644)
 		cobra.CheckErr(err)
 	}
 
+	if journalRollover {
+		journal, err := findNoteType(string(markdown.NoteTypeJournal))
+		cobra.CheckErr(err)
+		out.RolledOver, err = rolloverTasks(journal, now)
+		cobra.CheckErr(err)
+	}
+
+	printGenerated(out)
+}
+
+// carryOverSections copies the configured carry-over sections of the previous
+// journal, without completed tasks, into the matching sections of content, the
+// content of the named journal. Sections already holding content are left
+// alone, so a journal returned again by journal.create.cmd keeps its edits.
+func carryOverSections(content []byte, journalName string, previousJournalName string) ([]byte, error) {
+	previousContent, err := os.ReadFile(filepath.Join(journalDir, previousJournalName))
+	if err != nil {
+		return nil, err
+	}
+
+	parser := newParser()
+	previous, err := parser.ParseNoteContent(string(previousContent), journalSkipText, markdown.NoteTypeJournal, markdown.WithNoteName(previousJournalName))
+	if err != nil {
+		return nil, err
+	}
+	md, err := parser.ParseNoteContent(string(content), journalSkipText, markdown.NoteTypeJournal, markdown.WithNoteName(journalName))
+	if err != nil {
+		return nil, err
+	}
+
+	// splice from the end of the note so earlier offsets remain valid
+	for i := len(md.Sections) - 1; i >= 0; i-- {
+		section := md.Sections[i]
+		if !containsFold(journalCarryOverSections, section.Title) || strings.TrimSpace(section.Content) != "" {
+			continue
+		}
+
+		for _, previousSection := range previous.Sections {
+			if !strings.EqualFold(previousSection.Title, section.Title) {
+				continue
+			}
+
+			carried := strings.TrimSpace(markdown.DropCompletedTasks(previousSection.Content))
+			if carried == "" {
+				break
+			}
+
+			start := md.BodyOffset + section.ContentStart
+			end := md.BodyOffset + section.ContentEnd
+			replacement := "\n" + carried + "\n\n"
+			if section.ContentEnd == len(md.Body) {
+				replacement = "\n" + carried + "\n"
+			}
+			content = slices.Concat(content[:start:start], []byte(replacement), content[end:])
+			break
+		}
+	}
+
+	return content, nil
+}
+
+// containsFold reports whether s is within list, ignoring case
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(item string) bool {
+		return strings.EqualFold(item, s)
+	})
+}
+
+// noteLink returns the link from the note named fromName to the note named
+// toName, both relative to the same notes directory and without the .md
+// extension
+func noteLink(fromName string, toName string) string {
+	link, err := filepath.Rel(filepath.Dir(filepath.FromSlash(fromName)), filepath.FromSlash(toName))
+	if err != nil {
+		return toName
+	}
+	return filepath.ToSlash(link)
 }
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateJournalKeepsEditedSections(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	today := now.Format("2006-01-02")
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+
+	// the create command returns the journal of today, creating it when
+	// missing, as tools such as zk do
+	writeFiles(t, dir, map[string]string{
+		".standupnotes.yaml": fmt.Sprintf("journal:\n  dir: %s\n  create:\n    cmd: sh %s\nstandup:\n  dir: %s\n",
+			filepath.Join(dir, "journal"), filepath.Join(dir, "create.sh"), filepath.Join(dir, "standup")),
+		"create.sh": fmt.Sprintf("path=%s\n[ -e \"$path\" ] || printf '# Daily\\n\\n## Goals of the Day\\n\\n## Worked On\\n' > \"$path\"\necho \"$path\"\n",
+			filepath.Join(dir, "journal", today+".md")),
+		"journal/" + yesterday + ".md": "# Daily\n\n## Goals of the Day\n\n* [ ] Review PLA-77\n* [x] Deploy\n\n## Worked On\n\n* Caching\n",
+	})
+	config := filepath.Join(dir, ".standupnotes.yaml")
+
+	runCommand(t, config, "generate-journal")
+	journal := "journal/" + today + ".md"
+	if got := readFile(t, dir, journal); !strings.Contains(got, "## Goals of the Day\n\n* [ ] Review PLA-77\n\n## Worked On") {
+		t.Fatalf("open goals were not carried over:\n%s", got)
+	}
+
+	edited := strings.Replace(readFile(t, dir, journal), "* [ ] Review PLA-77\n", "* [x] Review PLA-77\n* [ ] Write up notes\n", 1)
+	writeFiles(t, dir, map[string]string{journal: edited})
+
+	runCommand(t, config, "generate-journal")
+	if got := readFile(t, dir, journal); got != edited {
+		t.Errorf("generating the journal again changed it:\n%s\nwant\n%s", got, edited)
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/index"
+	"github.com/rdark/standupnotes/internal/markdown"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	noIndex  bool
+	indexDir string
+)
+
+// noteIndex caches the notes parsed by loadNotesInRange, opened on first use
+var noteIndex *index.Index
+
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Manage the index of parsed notes",
+	Long: `Manage the index of parsed notes
+
+Notes parsed for ranges of notes (reports, search, tasks and issues) are kept in
+an index under index.dir (default .standupnotes/cache, relative to the config
+file), so they are only parsed again once they change. Notes are checked by their modification time and size,
+then by a hash of their content, and are parsed again whenever the
+configuration they are parsed with changes. Pass --no-index to bypass it`,
+}
+
+var indexRebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Rebuild the index from every note",
+	Run:   indexRebuildCmdFunc,
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noIndex, "no-index", false, "parse every note rather than using the index of parsed notes")
+	indexCmd.AddCommand(indexRebuildCmd)
+	rootCmd.AddCommand(indexCmd)
+}
+
+func indexRebuildCmdFunc(cmd *cobra.Command, args []string) {
+	if noIndex {
+		cobra.CheckErr(fmt.Errorf("the index cannot be rebuilt with --no-index"))
+	}
+
+	ix, err := openNoteIndex()
+	cobra.CheckErr(err)
+	ix.Clear()
+
+	start := time.Now()
+	for _, noteType := range noteTypes {
+		_, err := loadNotesInRange(noteType.Dir, noteType.FilenameFormat, noteType.SkipText, noteType.Type, time.Time{}, time.Now())
+		cobra.CheckErr(err)
+	}
+	cobra.CheckErr(saveNoteIndex())
+
+	out := indexOutput{Path: filepath.Join(indexDir, index.FileName), Notes: ix.Len()}
+	printOutput(out, func() {
+		fmt.Printf("Indexed %d notes into %s in %s\n", out.Notes, out.Path, time.Since(start).Round(time.Millisecond))
+	})
+}
+
+// indexOutput is a rebuilt index printed with --output
+type indexOutput struct {
+	// Path of the index
+	Path string `json:"path" yaml:"path"`
+	// Notes is the number of notes held by the index
+	Notes int `json:"notes" yaml:"notes"`
+}
+
+// openNoteIndex returns the index of parsed notes, or nil with --no-index
+func openNoteIndex() (*index.Index, error) {
+	if noIndex || noteIndex != nil {
+		return noteIndex, nil
+	}
+
+	if indexDir == "" {
+		indexDir = viper.GetString("index.dir")
+		if indexDir == "" {
+			indexDir = filepath.Join(".standupnotes", "cache")
+		}
+		// a relative index.dir is relative to the config file, so the same
+		// index is used whichever directory commands are run from
+		if config := viper.ConfigFileUsed(); config != "" && !filepath.IsAbs(indexDir) {
+			indexDir = filepath.Join(filepath.Dir(config), indexDir)
+		}
+	}
+
+	var err error
+	noteIndex, err = index.Open(indexDir)
+	return noteIndex, err
+}
+
+// saveNoteIndex writes the index of parsed notes to disk if it was used
+func saveNoteIndex() error {
+	if noteIndex == nil {
+		return nil
+	}
+	return noteIndex.Save()
+}
+
+// noteIndexKey identifies the configuration notes of noteType are parsed with
+// by loadNotesInRange, so indexed notes are parsed again when it changes
+func noteIndexKey(noteType markdown.NoteType, skipText []string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s|%q", noteType, skipText)
+	for _, t := range noteTypes {
+		fmt.Fprintf(&sb, "|%s=%s:%s", t.Type, t.Dir, t.FilenameFormat)
+	}
+	return sb.String()
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/rdark/standupnotes/internal/issues"
+	"github.com/rdark/standupnotes/internal/markdown"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	issuesFrom   string
+	issuesTo     string
+	issuesSince  string
+	issuesEnrich bool
+)
+
+var issuesCmd = &cobra.Command{
+	Use:   "issues",
+	Short: "List the issues worked on across the journals of a date range",
+	Long: `List the issues referenced by the work done sections of the journals in a date
+range, along with the days each issue was worked on
+
+Issues are recognised by the patterns configured with issues.patterns, by
+default Linear, Jira and GitHub issue URLs and GitHub org/repo#123 references.
+Each pattern has a tracker, a regular expression and optionally a key built
+from its named groups. Bare keys such as PLA-77 are recognised for the project
+prefixes given with issues.projects:
+
+issues:
+  projects: [PLA, OPS]
+  patterns:
+    - tracker: jira
+      pattern: 'https://example\.atlassian\.net/browse/(?P<key>[A-Z]+-\d+)'
+
+With --enrich the title and status of each issue is fetched from its tracker,
+configured with issues.linear.token, issues.jira.url and issues.jira.token
+(email:api-token), and issues.github.token. Keys such as PLA-77 not linked to a
+tracker are looked up in issues.default_tracker (default linear)
+	`,
+	Run: issuesCmdFunc,
+}
+
+func init() {
+	issuesCmd.PersistentFlags().StringVar(&issuesFrom, "from", "", "Start date of the range to list issues for")
+	issuesCmd.PersistentFlags().StringVar(&issuesTo, "to", "", "End date of the range to list issues for (default today)")
+	issuesCmd.PersistentFlags().StringVar(&issuesSince, "since", "7d", "Relative range to list issues for, e.g 7d, 2w, 1m")
+	issuesCmd.PersistentFlags().BoolVar(&issuesEnrich, "enrich", false, "Fetch the title and status of each issue from its tracker")
+	issuesCmd.PersistentFlags().StringSliceVar(&noteTags, "tag", []string{}, "Only include notes tagged with all of these front matter tags, e.g company:acme")
+	issuesCmd.MarkFlagsMutuallyExclusive("from", "since")
+	rootCmd.AddCommand(issuesCmd)
+}
+
+func issuesCmdFunc(cmd *cobra.Command, args []string) {
+	since := issuesSince
+	if issuesFrom != "" {
+		since = ""
+	}
+	start, end, err := parseDateRange(issuesFrom, issuesTo, since)
+	cobra.CheckErr(err)
+
+	matcher, err := newIssueMatcher()
+	cobra.CheckErr(err)
+
+	journals, err := loadNotesInRange(journalDir, journalFilenameFormat, journalSkipText, markdown.NoteTypeJournal, start, end)
+	cobra.CheckErr(err)
+
+	collector := issues.NewCollector(matcher)
+	for _, journal := range journals {
+		for _, section := range journal.Content.Sections {
+			if containsFold(journalWorkDoneSections, section.Title) {
+				collector.Add(journal.Date, section.Content)
+			}
+		}
+	}
+
+	if issuesEnrich {
+		defaultTracker := viper.GetString("issues.default_tracker")
+		if defaultTracker == "" {
+			defaultTracker = issues.TrackerLinear
+		}
+		// issues that could not be fetched are still listed
+		if err := issues.Enrich(context.Background(), collector.Issues(), newIssueClients(), defaultTracker); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to fetch issues: %v\n", err)
+		}
+	}
+
+	listed := collector.Issues()
+	if listed == nil {
+		listed = []*issues.Issue{}
+	}
+	printOutput(listed, func() {
+		printIssues(listed)
+	})
+}
+
+// printIssues prints each issue with its status, title and the dates it was
+// worked on
+func printIssues(listed []*issues.Issue) {
+	var sb strings.Builder
+	for _, issue := range listed {
+		sb.WriteString("* ")
+		if issue.URL != "" {
+			fmt.Fprintf(&sb, "[%s](%s)", issue.Key, issue.URL)
+		} else {
+			sb.WriteString(issue.Key)
+		}
+		if issue.Status != "" {
+			fmt.Fprintf(&sb, " [%s]", issue.Status)
+		}
+		if issue.Title != "" {
+			sb.WriteString(" " + issue.Title)
+		}
+
+		dates := make([]string, 0, len(issue.Dates))
+		for _, date := range issue.Dates {
+			dates = append(dates, date.Format("2006-01-02"))
+		}
+		fmt.Fprintf(&sb, " (%s)\n", strings.Join(dates, ", "))
+	}
+
+	printMarkdown(sb.String())
+}
+
+// newIssueMatcher returns a matcher for the patterns configured with
+// issues.patterns, or the default patterns
+func newIssueMatcher() (*issues.Matcher, error) {
+	var patterns []issues.PatternConfig
+	if err := viper.UnmarshalKey("issues.patterns", &patterns); err != nil {
+		return nil, fmt.Errorf("issues.patterns: %w", err)
+	}
+	if len(patterns) == 0 {
+		patterns = issues.DefaultPatterns
+	}
+	if projects := viper.GetStringSlice("issues.projects"); len(projects) > 0 {
+		patterns = append(slices.Clip(patterns), issues.ProjectKeyPattern(projects...))
+	}
+	return issues.NewMatcher(patterns)
+}
+
+// newIssueClients returns the clients for the configured trackers. GitHub is
+// always available, as public issues need no token.
+func newIssueClients() map[string]issues.Client {
+	clients := map[string]issues.Client{
+		issues.TrackerGitHub: issues.NewGitHubClient(viper.GetString("issues.github.url"), viper.GetString("issues.github.token")),
+	}
+	if token := viper.GetString("issues.linear.token"); token != "" {
+		clients[issues.TrackerLinear] = issues.NewLinearClient(viper.GetString("issues.linear.url"), token)
+	}
+	if url := viper.GetString("issues.jira.url"); url != "" {
+		clients[issues.TrackerJira] = issues.NewJiraClient(url, viper.GetString("issues.jira.token"))
+	}
+	return clients
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/rdark/standupnotes/internal/diff"
+	"github.com/rdark/standupnotes/internal/markdown"
+	"github.com/rdark/standupnotes/internal/util"
+
+	"github.com/spf13/cobra"
+)
+
+var fixLinksNoteTypes []string
+
+var fixLinksCmd = &cobra.Command{
+	Use:   "fix-links",
+	Short: "Fix the links to the previous and next notes across every note",
+	Long: `Fix the links to the previous and next notes within every journal and standup
+
+The previous and next notes are the notes that exist before and after each note,
+so days without a note such as weekends and holidays are skipped. Links are
+matched by their titles (journal.link_previous_titles and
+journal.link_next_titles for journals), and links with a .md extension keep it.
+The changes to each note are printed before it is written`,
+	Run: fixLinksCmdFunc,
+}
+
+func init() {
+	fixLinksCmd.PersistentFlags().StringSliceVarP(&fixLinksNoteTypes, "type", "t", []string{string(markdown.NoteTypeJournal), string(markdown.NoteTypeStandup)}, "Types of the notes to fix links within")
+	rootCmd.AddCommand(fixLinksCmd)
+}
+
+func fixLinksCmdFunc(cmd *cobra.Command, args []string) {
+	out := make([]fixedLinksOutput, 0)
+	for _, name := range fixLinksNoteTypes {
+		noteType, err := findNoteType(name)
+		cobra.CheckErr(err)
+
+		names, err := util.GetMdFileNames(noteType.Dir, noteType.FilenameFormat)
+		cobra.CheckErr(err)
+
+		for i, name := range names {
+			var previous, next string
+			if i > 0 {
+				previous = names[i-1]
+			}
+			if i < len(names)-1 {
+				next = names[i+1]
+			}
+
+			notePath := filepath.Join(noteType.Dir, name)
+			content, err := noteWriter.ReadFile(notePath)
+			cobra.CheckErr(err)
+
+			fixed, links, err := fixAdjacentLinks(noteType, name, content, previous, next)
+			cobra.CheckErr(err)
+			if bytes.Equal(fixed, content) {
+				continue
+			}
+
+			// a dry run prints the diff itself
+			if !noteWriter.DryRun && !structuredOutput() {
+				fmt.Print(diff.Unified(notePath, notePath, content, fixed))
+			}
+			cobra.CheckErr(noteWriter.WriteFile(notePath, fixed, 0644))
+			out = append(out, fixedLinksOutput{Path: notePath, Links: links})
+		}
+	}
+
+	// the changes have been printed as diffs
+	printOutput(out, func() {})
+}
+
+// fixedLinksOutput is a note with fixed links printed with --output
+type fixedLinksOutput struct {
+	// Path of the note
+	Path string `json:"path" yaml:"path"`
+	// Links that were fixed, with their previous targets
+	Links []markdown.AdjacentLink `json:"links" yaml:"links"`
+}
+
+// fixAdjacentLinks returns content, the content of the named note of
+// noteType, with the links to the previous and next notes of the same type
+// pointing at the notes named previousName and nextName, along with the links
+// that were changed. Links are left alone when the name is empty.
+func fixAdjacentLinks(noteType *noteTypeConfig, name string, content []byte, previousName string, nextName string) ([]byte, []markdown.AdjacentLink, error) {
+	parser := newParser()
+	md, err := parser.ParseNoteContent(string(content), noteType.SkipText, noteType.Type, markdown.WithNoteName(name))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	links, targets := adjacentLinkFixes(noteType, name, md, previousName, nextName)
+	return markdown.ReplaceLinkTargets(content, md, links, targets), links, nil
+}
+
+// adjacentLinkFixes returns the links within md, the parsed content of the
+// named note of noteType, to the previous and next notes of the same type that
+// do not point at the notes named previousName and nextName, along with the
+// targets they should point at. Links are left alone when the name is empty.
+func adjacentLinkFixes(noteType *noteTypeConfig, name string, md *markdown.NoteContent, previousName string, nextName string) ([]markdown.AdjacentLink, []string) {
+	var links []markdown.AdjacentLink
+	var targets []string
+	for _, link := range md.AdjacentLinks {
+		if link.TargetNoteType != noteType.Type {
+			continue
+		}
+
+		var target string
+		switch {
+		case previousName != "" && slices.Contains(noteType.LinkPreviousTitles, link.Title):
+			target = noteLink(name, strings.TrimSuffix(previousName, ".md"))
+		case nextName != "" && slices.Contains(noteType.LinkNextTitles, link.Title):
+			target = noteLink(name, strings.TrimSuffix(nextName, ".md"))
+		default:
+			continue
+		}
+		// keep the extension style of the existing link
+		if strings.HasSuffix(link.Target, ".md") {
+			target += ".md"
+		}
+
+		if !sameNoteLink(link.Target, target) {
+			links = append(links, link)
+			targets = append(targets, target)
+		}
+	}
+
+	return links, targets
+}
+
+// sameNoteLink reports whether the link targets point at the same note,
+// ignoring the .md extension and equivalent relative forms such as ./name
+func sameNoteLink(a string, b string) bool {
+	normalise := func(target string) string {
+		return strings.TrimSuffix(path.Clean(target), ".md")
+	}
+	return normalise(a) == normalise(b)
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestSameNoteLink(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"2024-12-11", "2024-12-11", true},
+		{"2024-12-11.md", "2024-12-11", true},
+		{"./2024-12-11.md", "2024-12-11", true},
+		{"../2024/12/../12/2024-12-11", "../2024/12/2024-12-11", true},
+		{"2024-12-10.md", "2024-12-11", false},
+		{"../standup/2024-12-11", "2024-12-11", false},
+	}
+	for _, tt := range tests {
+		if got := sameNoteLink(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameNoteLink(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFixLinks(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".standupnotes.yaml":    fmt.Sprintf("journal:\n  dir: %s\nstandup:\n  dir: %s\n", filepath.Join(dir, "journal"), filepath.Join(dir, "standup")),
+		"journal/2024-12-09.md": "# Daily\n",
+		"journal/2024-12-11.md": "# Daily\n\n* [Yesterday](./2024-12-10.md) - [Tomorrow](<2024-12-12>)\n\n[Yesterday]: stays\n",
+		"journal/2024-12-13.md": "# Daily\n\n[Yesterday]( 2024-12-10 \"title\" )  \n[Tomorrow](2024-12-14)\n",
+	})
+
+	runCommand(t, filepath.Join(dir, ".standupnotes.yaml"), "fix-links", "--type", "journal")
+
+	want := map[string]string{
+		"journal/2024-12-09.md": "# Daily\n",
+		"journal/2024-12-11.md": "# Daily\n\n* [Yesterday](2024-12-09.md) - [Tomorrow](<2024-12-13>)\n\n[Yesterday]: stays\n",
+		// the last note has no next note to link to
+		"journal/2024-12-13.md": "# Daily\n\n[Yesterday]( 2024-12-11 \"title\" )  \n[Tomorrow](2024-12-14)\n",
+	}
+	for name, want := range want {
+		if got := readFile(t, dir, name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
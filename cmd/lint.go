@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/lint"
+	"github.com/rdark/standupnotes/internal/markdown"
+	"github.com/rdark/standupnotes/internal/util"
+
+	"github.com/spf13/cobra"
+)
+
+var lintNoteTypes []string
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check journals and standups for problems",
+	Long: `Check every journal and standup for:
+
+* missing required sections (required_sections)
+* links to other notes pointing at notes that do not exist
+* links to the previous and next notes not matching the notes either side
+* lists nested deeper than the parser allows
+* malformed front matter
+* front matter dates not matching the date of the note
+
+Problems are printed as file:line: message, or as JSON or YAML with --output. The
+command exits non-zero when any errors are found, so it can be used as a
+pre-commit hook`,
+	Run: lintCmdFunc,
+}
+
+func init() {
+	lintCmd.PersistentFlags().StringSliceVarP(&lintNoteTypes, "type", "t", []string{string(markdown.NoteTypeJournal), string(markdown.NoteTypeStandup)}, "Types of the notes to check")
+	rootCmd.AddCommand(lintCmd)
+}
+
+func lintCmdFunc(cmd *cobra.Command, args []string) {
+	// the newest note of each type, links to notes after which are not expected to exist yet
+	newest := make(map[markdown.NoteType]time.Time)
+	for _, noteType := range noteTypes {
+		names, err := util.GetMdFileNames(noteType.Dir, noteType.FilenameFormat)
+		cobra.CheckErr(err)
+		if len(names) > 0 {
+			newest[noteType.Type], _ = noteType.FilenameFormat.Parse(names[len(names)-1])
+		}
+	}
+
+	var issues []lint.Issue
+	for _, name := range lintNoteTypes {
+		noteType, err := findNoteType(name)
+		cobra.CheckErr(err)
+
+		names, err := util.GetMdFileNames(noteType.Dir, noteType.FilenameFormat)
+		cobra.CheckErr(err)
+
+		for i, name := range names {
+			var previous, next string
+			if i > 0 {
+				previous = names[i-1]
+			}
+			if i < len(names)-1 {
+				next = names[i+1]
+			}
+
+			noteIssues, err := lintNote(noteType, name, previous, next, newest)
+			cobra.CheckErr(err)
+			issues = append(issues, noteIssues...)
+		}
+	}
+
+	if issues == nil {
+		issues = []lint.Issue{}
+	}
+	printOutput(issues, func() {
+		cobra.CheckErr(lint.WriteText(os.Stdout, issues))
+	})
+
+	if lint.HasErrors(issues) {
+		os.Exit(1)
+	}
+}
+
+// lintNote returns the problems found within the named note of noteType,
+// which sits between the notes named previous and next
+func lintNote(noteType *noteTypeConfig, name string, previous string, next string, newest map[markdown.NoteType]time.Time) ([]lint.Issue, error) {
+	notePath := filepath.Join(noteType.Dir, name)
+	file := displayPath(notePath)
+
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		return nil, err
+	}
+
+	date, _ := noteType.FilenameFormat.Parse(name)
+	issues := lint.CheckFrontMatter(file, content, date)
+
+	parser := newParser()
+	md, err := parser.ParseNoteContent(string(content), noteType.SkipText, noteType.Type, markdown.WithNoteName(name))
+	if errors.Is(err, markdown.ErrInvalidFrontMatter) {
+		// reported by CheckFrontMatter
+		return issues, nil
+	}
+	var depthErr *markdown.ListDepthError
+	if errors.As(err, &depthErr) {
+		issues = append(issues, lint.Issue{File: file, Line: lint.LineAt(content, depthErr.Offset), Severity: lint.SeverityError, Message: err.Error()})
+		// parse the note again allowing the list, to carry on checking it
+		md, err = parser.ParseNoteContent(string(content), noteType.SkipText, noteType.Type, markdown.WithNoteName(name), markdown.WithDeepLists())
+	}
+	if err != nil {
+		return append(issues, lint.Issue{File: file, Line: 1, Severity: lint.SeverityError, Message: err.Error()}), nil
+	}
+
+	if md.MarkerError != nil {
+		issues = append(issues, lint.Issue{
+			File:     file,
+			Line:     lint.LineAt(content, md.MarkerError.Offset),
+			Severity: lint.SeverityError,
+			Message:  md.MarkerError.Error(),
+		})
+	}
+	issues = append(issues, lint.CheckSections(file, md, noteType.RequiredSections)...)
+
+	// links which are both missing and not the expected previous or next
+	// note are reported once, with the note expected
+	expected := make(map[int]string)
+	links, targets := adjacentLinkFixes(noteType, name, md, previous, next)
+	for i, link := range links {
+		expected[link.LinkStart] = targets[i]
+	}
+
+	checked := make(map[int]bool)
+	for _, link := range md.AdjacentLinks {
+		// links with several text nodes are reported once
+		if checked[link.LinkStart] {
+			continue
+		}
+		checked[link.LinkStart] = true
+
+		missing, err := isMissingNote(notePath, link, newest)
+		if err != nil {
+			return nil, err
+		}
+
+		var message string
+		target, ok := expected[link.LinkStart]
+		switch {
+		case ok && missing:
+			message = fmt.Sprintf("link %q points at %s, which does not exist, expected %s", link.Title, link.Target, target)
+		case ok:
+			message = fmt.Sprintf("link %q points at %s, expected %s", link.Title, link.Target, target)
+		case missing:
+			message = fmt.Sprintf("link %q points at %s, which does not exist", link.Title, link.Target)
+		default:
+			continue
+		}
+		issues = append(issues, lint.Issue{
+			File:     file,
+			Line:     lint.LineAt(content, md.BodyOffset+link.LinkStart),
+			Severity: lint.SeverityError,
+			Message:  message,
+		})
+	}
+
+	return issues, nil
+}
+
+// isMissingNote reports whether the link within the note at notePath points at
+// a note that does not exist, ignoring notes dated after the newest note of
+// their type
+func isMissingNote(notePath string, link markdown.AdjacentLink, newest map[markdown.NoteType]time.Time) (bool, error) {
+	targetPath := filepath.Join(filepath.Dir(notePath), filepath.FromSlash(link.Target))
+	if !strings.HasSuffix(targetPath, ".md") {
+		targetPath += ".md"
+	}
+
+	_, err := os.Stat(targetPath)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+
+	targetType, err := findNoteType(string(link.TargetNoteType))
+	if err != nil {
+		return true, nil
+	}
+	targetName, err := filepath.Rel(targetType.Dir, targetPath)
+	if err != nil {
+		return true, nil
+	}
+	if date, ok := targetType.FilenameFormat.Parse(filepath.ToSlash(targetName)); ok && date.After(newest[targetType.Type]) {
+		return false, nil
+	}
+	return true, nil
+}
+
+// displayPath returns path relative to the working directory when within it
+func displayPath(path string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(cwd, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
+}
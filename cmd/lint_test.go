@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/lint"
+	"github.com/rdark/standupnotes/internal/markdown"
+)
+
+func TestLintNoteCarriesOnAfterParseErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".standupnotes.yaml":    fmt.Sprintf("journal:\n  dir: %s\n  required_sections: [Goals of the Day]\nstandup:\n  dir: %s\n", filepath.Join(dir, "journal"), filepath.Join(dir, "standup")),
+		"journal/2024-12-11.md": "# Daily\n",
+	})
+	// the configuration is loaded by running a command reading the notes
+	runCommand(t, filepath.Join(dir, ".standupnotes.yaml"), "tasks")
+
+	writeFiles(t, dir, map[string]string{
+		"journal/2024-12-12.md": `# Daily
+
+[Yesterday](2024-12-10)
+
+## Worked On
+
+* a
+    * b
+        * c
+            * too deep
+
+<!-- standup:begin today -->
+`,
+	})
+
+	journal, err := findNoteType("journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues, err := lintNote(journal, "2024-12-12.md", "2024-12-11.md", "", map[markdown.NoteType]time.Time{markdown.NoteTypeJournal: time.Date(2024, 12, 12, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := displayPath(filepath.Join(dir, "journal", "2024-12-12.md"))
+	want := []lint.Issue{
+		{File: file, Line: 10, Severity: lint.SeverityError, Message: "list nesting too deep: maximum allowed is 3 levels"},
+		{File: file, Line: 12, Severity: lint.SeverityError, Message: `unmatched begin marker for region "today"`},
+		{File: file, Line: 1, Severity: lint.SeverityError, Message: `missing required section "Goals of the Day"`},
+		{File: file, Line: 3, Severity: lint.SeverityError, Message: `link "Yesterday" points at 2024-12-10, which does not exist, expected 2024-12-11`},
+	}
+	if !reflect.DeepEqual(issues, want) {
+		t.Errorf("lintNote() = %+v\nwant %+v", issues, want)
+	}
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/rdark/standupnotes/internal/markdown"
+	"github.com/rdark/standupnotes/internal/util"
+
+	"github.com/spf13/viper"
+)
+
+// noteTypeConfig is the configuration of a registered note type
+type noteTypeConfig struct {
+	// Type of the notes
+	Type markdown.NoteType
+	// Dir holding the notes
+	Dir string
+	// FilenameFormat the notes are named with
+	FilenameFormat *util.FilenameFormat
+	// Sections of newly generated notes
+	Sections []string
+	// SkipText lines skipped when parsing the notes
+	SkipText []string
+	// LinkPreviousTitles are the titles of links to the previous note
+	LinkPreviousTitles []string
+	// LinkNextTitles are the titles of links to the next note
+	LinkNextTitles []string
+	// Template new notes are rendered from, empty for the built-in template
+	Template string
+	// RolloverSections hold the tasks rolled over into the next note
+	RolloverSections []string
+	// RolloverTarget is the section of the next note tasks are rolled over into
+	RolloverTarget string
+	// RequiredSections every note must hold
+	RequiredSections []string
+}
+
+// customNoteType is a note type as configured under note_types
+type customNoteType struct {
+	Dir                string   `mapstructure:"dir"`
+	FilenameFormat     string   `mapstructure:"filename_format"`
+	Sections           []string `mapstructure:"sections"`
+	SkipText           []string `mapstructure:"skip_text"`
+	LinkPreviousTitles []string `mapstructure:"link_previous_titles"`
+	LinkNextTitles     []string `mapstructure:"link_next_titles"`
+	Template           string   `mapstructure:"template"`
+	RolloverSections   []string `mapstructure:"rollover_sections"`
+	RolloverTarget     string   `mapstructure:"rollover_target_section"`
+	RequiredSections   []string `mapstructure:"required_sections"`
+}
+
+// noteTypes are the registered note types, journal and standup first
+var noteTypes []*noteTypeConfig
+
+// loadNoteTypes registers the built-in note types from the journal and
+// standup configuration, followed by the note types configured under
+// note_types
+func loadNoteTypes() error {
+	noteTypes = []*noteTypeConfig{
+		{
+			Type:               markdown.NoteTypeJournal,
+			Dir:                journalDir,
+			FilenameFormat:     journalFilenameFormat,
+			Sections:           journalCarryOverSections,
+			SkipText:           journalSkipText,
+			LinkPreviousTitles: journalLinkPreviousTitles,
+			LinkNextTitles:     journalLinkNextTitles,
+			Template:           viper.GetString("journal.template"),
+			RolloverSections:   viper.GetStringSlice("journal.rollover_sections"),
+			RolloverTarget:     viper.GetString("journal.rollover_target_section"),
+			RequiredSections:   viper.GetStringSlice("journal.required_sections"),
+		},
+		{
+			Type:               markdown.NoteTypeStandup,
+			Dir:                standupDir,
+			FilenameFormat:     standupFilenameFormat,
+			Sections:           []string{standupWorkDoneSection, standupTodaySection},
+			SkipText:           standupSkipText,
+			LinkPreviousTitles: []string{"Standup Yesterday"},
+			LinkNextTitles:     []string{"Standup Tomorrow"},
+			Template:           viper.GetString("standup.template"),
+			RolloverSections:   viper.GetStringSlice("standup.rollover_sections"),
+			RolloverTarget:     viper.GetString("standup.rollover_target_section"),
+			RequiredSections:   viper.GetStringSlice("standup.required_sections"),
+		},
+	}
+	if len(noteTypes[0].RolloverSections) == 0 {
+		noteTypes[0].RolloverSections = journalGoalsSections
+	}
+	if len(noteTypes[1].RolloverSections) == 0 {
+		noteTypes[1].RolloverSections = []string{standupTodaySection}
+	}
+	if len(noteTypes[0].RequiredSections) == 0 {
+		noteTypes[0].RequiredSections = journalGoalsSections
+	}
+	if len(noteTypes[1].RequiredSections) == 0 {
+		noteTypes[1].RequiredSections = []string{standupWorkDoneSection, standupTodaySection}
+	}
+
+	var custom map[string]customNoteType
+	if err := viper.UnmarshalKey("note_types", &custom); err != nil {
+		return fmt.Errorf("invalid note_types: %w", err)
+	}
+
+	names := make([]string, 0, len(custom))
+	for name := range custom {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		config := custom[name]
+		if _, err := findNoteType(name); err == nil {
+			return fmt.Errorf("note type %s is built in; configure it under %s instead of note_types", name, name)
+		}
+		if config.Dir == "" {
+			return fmt.Errorf("note type %s: dir is required", name)
+		}
+
+		dir, err := filepath.Abs(config.Dir)
+		if err != nil {
+			return err
+		}
+		format, err := util.ParseFilenameFormat(config.FilenameFormat)
+		if err != nil {
+			return fmt.Errorf("note type %s: %w", name, err)
+		}
+
+		noteType := &noteTypeConfig{
+			Type:               markdown.NoteType(name),
+			Dir:                dir,
+			FilenameFormat:     format,
+			Sections:           config.Sections,
+			SkipText:           config.SkipText,
+			LinkPreviousTitles: config.LinkPreviousTitles,
+			LinkNextTitles:     config.LinkNextTitles,
+			Template:           config.Template,
+			RolloverSections:   config.RolloverSections,
+			RolloverTarget:     config.RolloverTarget,
+			RequiredSections:   config.RequiredSections,
+		}
+		if len(noteType.LinkPreviousTitles) == 0 {
+			noteType.LinkPreviousTitles = []string{"Previous"}
+		}
+		if len(noteType.LinkNextTitles) == 0 {
+			noteType.LinkNextTitles = []string{"Next"}
+		}
+		if len(noteType.RequiredSections) == 0 {
+			noteType.RequiredSections = noteType.Sections
+		}
+		noteTypes = append(noteTypes, noteType)
+	}
+
+	for _, noteType := range noteTypes {
+		if noteType.RolloverTarget == "" && len(noteType.RolloverSections) > 0 {
+			noteType.RolloverTarget = noteType.RolloverSections[0]
+		}
+	}
+
+	return nil
+}
+
+// findNoteType returns the registered note type with the given name
+func findNoteType(name string) (*noteTypeConfig, error) {
+	for _, noteType := range noteTypes {
+		if string(noteType.Type) == name {
+			return noteType, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown note type %q, expected one of: %s", name, strings.Join(noteTypeNames(), ", "))
+}
+
+// noteTypeNames returns the names of the registered note types
+func noteTypeNames() []string {
+	names := make([]string, 0, len(noteTypes))
+	for _, noteType := range noteTypes {
+		names = append(names, string(noteType.Type))
+	}
+	return names
+}
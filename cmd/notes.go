@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/loader"
+	"github.com/rdark/standupnotes/internal/markdown"
+	"github.com/rdark/standupnotes/internal/util"
+)
+
+// noteTags are the front matter tags notes must all be tagged with to be
+// loaded by loadNotesInRange (--tag)
+var noteTags []string
+
+// datedNote is a parsed note along with the date it is for
+type datedNote struct {
+	// Date of the note
+	Date time.Time
+	// Name of the note file
+	Name string
+	// Parsed content of the note
+	Content *markdown.NoteContent
+}
+
+// loadNotesInRange parses the notes in noteDir named with format and dated
+// from start to end inclusive, oldest first. Notes missing any of the tags
+// given with --tag are skipped.
+func loadNotesInRange(noteDir string, format *util.FilenameFormat, skipText []string, noteType markdown.NoteType, start time.Time, end time.Time) ([]datedNote, error) {
+	names, err := util.GetMdFileNamesInRange(noteDir, format, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]loader.Source, 0, len(names))
+	for _, name := range names {
+		dt, ok := format.Parse(name)
+		if !ok {
+			return nil, fmt.Errorf("%s: does not match filename format %s", name, format)
+		}
+		sources = append(sources, loader.Source{Name: name, Path: filepath.Join(noteDir, filepath.FromSlash(name)), Date: dt})
+	}
+
+	ix, err := openNoteIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	parser := newParser()
+	load := loader.ParseFile(parser, skipText, noteType)
+	if ix != nil {
+		key := noteIndexKey(noteType, skipText)
+		load = func(ctx context.Context, source loader.Source) (*markdown.NoteContent, error) {
+			return ix.Parse(source.Path, key, func(content []byte) (*markdown.NoteContent, error) {
+				return parser.ParseNoteContent(string(content), skipText, noteType, markdown.WithNoteName(source.Name))
+			})
+		}
+	}
+
+	loaded, err := loader.New(load).LoadAll(context.Background(), sources)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := make([]datedNote, 0, len(loaded))
+	for _, note := range loaded {
+		if !hasTags(note.Content, noteTags) {
+			continue
+		}
+		notes = append(notes, datedNote{Date: note.Date, Name: note.Name, Content: note.Content})
+	}
+
+	return notes, nil
+}
+
+// hasTags reports whether the front matter of the note is tagged with every
+// one of tags
+func hasTags(md *markdown.NoteContent, tags []string) bool {
+	for _, tag := range tags {
+		if !md.FrontMatter.HasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseLocalDate parses a YYYY-MM-DD date given on the command line in local
+// time, as dates relative to today are
+func parseLocalDate(date string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", date, time.Local)
+}
+
+// parseDateRange returns the range starting at from, or the relative range
+// since (e.g 7d) before to, and ending at to (default today)
+func parseDateRange(from string, to string, since string) (time.Time, time.Time, error) {
+	end := time.Now()
+	var start time.Time
+	var err error
+
+	if to != "" {
+		end, err = parseLocalDate(to)
+		if err != nil {
+			return start, end, err
+		}
+	}
+
+	switch {
+	case since != "":
+		start, err = util.ParseSince(since, end)
+	case from != "":
+		start, err = parseLocalDate(from)
+	default:
+		err = fmt.Errorf("--to requires --from or --since")
+	}
+	if err != nil {
+		return start, end, err
+	}
+
+	if start.After(end) {
+		return start, end, fmt.Errorf("start of range %s is after the end %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+	return start, end, nil
+}
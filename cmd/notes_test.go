@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDateRange(t *testing.T) {
+	// far from UTC, so that dates parsed in UTC fall on another day to now
+	local := time.Local
+	time.Local = time.FixedZone("UTC+14", 14*60*60)
+	defer func() {
+		time.Local = local
+	}()
+	today := time.Now().Format("2006-01-02")
+
+	tests := []struct {
+		name  string
+		from  string
+		to    string
+		since string
+		start string
+		end   string
+	}{
+		{name: "from today", from: today, start: today, end: today},
+		{name: "from to", from: "2024-12-09", to: "2024-12-12", start: "2024-12-09", end: "2024-12-12"},
+		{name: "since to", to: "2024-12-12", since: "1w", start: "2024-12-05", end: "2024-12-12"},
+		{name: "since today", since: "0d", start: today, end: today},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := parseDateRange(tt.from, tt.to, tt.since)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if start.Location() != time.Local || end.Location() != time.Local {
+				t.Errorf("got range in %s to %s, want local time", start.Location(), end.Location())
+			}
+			if got := start.Format("2006-01-02"); got != tt.start {
+				t.Errorf("got start %s, want %s", got, tt.start)
+			}
+			if got := end.Format("2006-01-02"); got != tt.end {
+				t.Errorf("got end %s, want %s", got, tt.end)
+			}
+		})
+	}
+
+	if _, _, err := parseDateRange("2024-12-13", "2024-12-12", ""); err == nil {
+		t.Error("got no error for a range starting after its end")
+	}
+}
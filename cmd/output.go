@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/rdark/standupnotes/internal/markdown"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Output types of the results of commands (--output)
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+// outputTypes are the supported output types
+var outputTypes = []string{outputText, outputJSON, outputYAML}
+
+// checkOutputType returns an error for an unsupported --output
+func checkOutputType() error {
+	if !slices.Contains(outputTypes, outputType) {
+		return fmt.Errorf("unsupported output %q: must be one of %s", outputType, strings.Join(outputTypes, ", "))
+	}
+	return nil
+}
+
+// structuredOutput reports whether results are printed as JSON or YAML
+// rather than text
+func structuredOutput() bool {
+	return outputType != outputText
+}
+
+// printOutput prints v as JSON or YAML when requested with --output, or else
+// prints the results as text with printText
+func printOutput(v interface{}, printText func()) {
+	switch outputType {
+	case outputJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		cobra.CheckErr(encoder.Encode(v))
+	case outputYAML:
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		cobra.CheckErr(encoder.Encode(v))
+		cobra.CheckErr(encoder.Close())
+	default:
+		printText()
+	}
+}
+
+// noteOutput is a parsed note printed with --output
+type noteOutput struct {
+	// Type of the note
+	Type markdown.NoteType `json:"type" yaml:"type"`
+	// Name of the note within the directory of its type
+	Name string `json:"name" yaml:"name"`
+	// Path of the note
+	Path string `json:"path" yaml:"path"`
+	// Date of the note, when its name holds one
+	Date string `json:"date,omitempty" yaml:"date,omitempty"`
+
+	*markdown.NoteContent `yaml:",inline"`
+}
+
+// newNoteOutput returns the output of md, the parsed content of the named note
+// of noteType
+func newNoteOutput(noteType *noteTypeConfig, name string, md *markdown.NoteContent) noteOutput {
+	out := noteOutput{
+		Type:        noteType.Type,
+		Name:        name,
+		Path:        filepath.Join(noteType.Dir, filepath.FromSlash(name)),
+		NoteContent: md,
+	}
+	if dt, ok := noteType.FilenameFormat.Parse(name); ok {
+		out.Date = dt.Format("2006-01-02")
+	}
+	return out
+}
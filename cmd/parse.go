@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/markdown"
+	"github.com/rdark/standupnotes/internal/util"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	parseDate     string
+	parseNoteType string
+)
+
+var parseCmd = &cobra.Command{
+	Use:   "parse [file]",
+	Short: "Print the parsed content of a note",
+	Long: `Print everything parsed from a note: its front matter, the sections with their
+offsets, the links to other notes, the tasks and the links to URLs
+
+The note is given as a path, or as the most recent note of --type on or before
+--date. The type of a note given as a path is found from the directory it is in
+unless given with --type.
+
+The note is printed as YAML, or as JSON with --output json, e.g
+
+  standupnotes parse -o json notes/journal/2024-12-12.md | jq '.tasks[].text'`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  parseCmdFunc,
+}
+
+func init() {
+	parseCmd.PersistentFlags().StringVarP(&parseDate, "date", "d", time.Now().Format("2006-01-02"), "Date of the note to parse")
+	parseCmd.PersistentFlags().StringVarP(&parseNoteType, "type", "t", "", "Type of the note to parse (default journal, or the type of the note given as a path)")
+	rootCmd.AddCommand(parseCmd)
+}
+
+func parseCmdFunc(cmd *cobra.Command, args []string) {
+	var noteType *noteTypeConfig
+	var name string
+	var err error
+	if len(args) > 0 {
+		noteType, name, err = findNoteByPath(args[0], parseNoteType)
+	} else {
+		noteType, name, err = findNoteByDate(parseDate, parseNoteType)
+	}
+	cobra.CheckErr(err)
+
+	content, err := os.ReadFile(filepath.Join(noteType.Dir, filepath.FromSlash(name)))
+	cobra.CheckErr(err)
+
+	parser := newParser()
+	md, err := parser.ParseNoteContent(string(content), noteType.SkipText, noteType.Type, markdown.WithNoteName(name))
+	cobra.CheckErr(err)
+
+	out := newNoteOutput(noteType, name, md)
+	if outputType == outputText {
+		// there is no text form of a parsed note
+		outputType = outputYAML
+	}
+	printOutput(out, nil)
+}
+
+// findNoteByPath returns the note type and name of the note at notePath,
+// found from the directory of each note type unless typeName is given
+func findNoteByPath(notePath string, typeName string) (*noteTypeConfig, string, error) {
+	notePath, err := filepath.Abs(notePath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, noteType := range noteTypes {
+		if typeName != "" && string(noteType.Type) != typeName {
+			continue
+		}
+		name, err := filepath.Rel(noteType.Dir, notePath)
+		if err != nil || strings.HasPrefix(name, "..") {
+			continue
+		}
+		return noteType, filepath.ToSlash(name), nil
+	}
+
+	if typeName != "" {
+		if _, err := findNoteType(typeName); err != nil {
+			return nil, "", err
+		}
+		return nil, "", fmt.Errorf("%s is not within the %s directory", notePath, typeName)
+	}
+	return nil, "", fmt.Errorf("%s is not within the directory of any note type", notePath)
+}
+
+// findNoteByDate returns the note type and name of the most recent note of the
+// named type (default journal) on or before date
+func findNoteByDate(date string, typeName string) (*noteTypeConfig, string, error) {
+	if typeName == "" {
+		typeName = string(markdown.NoteTypeJournal)
+	}
+	noteType, err := findNoteType(typeName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	dt, err := parseLocalDate(date)
+	if err != nil {
+		return nil, "", err
+	}
+
+	name, err := util.GetMostRecentMdFileName(noteType.Dir, noteType.FilenameFormat, dt)
+	if err != nil {
+		return nil, "", err
+	}
+	if name == "" {
+		return nil, "", fmt.Errorf("no %s found within 30 days of %s", noteType.Type, date)
+	}
+	return noteType, name, nil
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/markdown"
+	"github.com/rdark/standupnotes/internal/slack"
+	"github.com/rdark/standupnotes/internal/util"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	postDate        string
+	slackWebhookURL string
+	slackRetries    int
+)
+
+var postStandupCmd = &cobra.Command{
+	Use:   "post-standup",
+	Short: "Post the standup for a given day to Slack",
+	Long: `Post the standup note for a given day to the Slack incoming webhook configured
+with standup.slack.webhook_url
+If a standup note does not exist for the given day, the standup directory will
+be searched backwards for the newest standup within 30 days of the given date
+	`,
+	Run: postStandupCmdFunc,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		rootCmd.PersistentPreRun(cmd, args)
+		if slackWebhookURL == "" {
+			slackWebhookURL = viper.GetString("standup.slack.webhook_url")
+		}
+	},
+}
+
+func init() {
+	postStandupCmd.PersistentFlags().StringVarP(&postDate, "date", "d", time.Now().Format("2006-01-02"), "Date to post the standup for")
+	postStandupCmd.PersistentFlags().StringVar(&slackWebhookURL, "webhook-url", "", "Slack incoming webhook URL")
+	postStandupCmd.PersistentFlags().IntVar(&slackRetries, "retries", 3, "Number of times to retry a rate limited or failed post")
+	rootCmd.AddCommand(postStandupCmd)
+}
+
+func postStandupCmdFunc(cmd *cobra.Command, args []string) {
+	if slackWebhookURL == "" && !dryRun {
+		cobra.CheckErr(fmt.Errorf("No Slack webhook configured to post standup notes"))
+	}
+
+	dt, err := parseLocalDate(postDate)
+	cobra.CheckErr(err)
+
+	mostRecentStandup, err := util.GetMostRecentMdFileName(standupDir, standupFilenameFormat, dt)
+	cobra.CheckErr(err)
+	if mostRecentStandup == "" {
+		cobra.CheckErr(fmt.Errorf("no standup found within 30 days of %s", postDate))
+	}
+
+	content, err := os.ReadFile(path.Join(standupDir, mostRecentStandup))
+	cobra.CheckErr(err)
+
+	renderer, err := markdown.NewRenderer(markdown.FormatSlack)
+	cobra.CheckErr(err)
+
+	parser := newParser(markdown.WithRenderer(renderer))
+	md, err := parser.ParseNoteContent(string(content), standupSkipText, markdown.NoteTypeStandup, markdown.WithNoteName(mostRecentStandup))
+	cobra.CheckErr(err)
+
+	var title string
+	var sections []slack.Section
+	for i, section := range md.Sections {
+		// the title heading of the note is used as the message header
+		if i == 0 && strings.TrimSpace(section.Content) == "" {
+			title = section.Title
+			continue
+		}
+		if strings.TrimSpace(section.Content) == "" {
+			continue
+		}
+		sections = append(sections, slack.Section{Title: section.Title, Content: section.Content})
+	}
+
+	msg := slack.NewMessage(title, sections)
+
+	client := slack.NewClient(slackWebhookURL)
+	client.MaxRetries = slackRetries
+	client.DryRun = dryRun
+
+	err = client.Post(context.Background(), msg)
+	cobra.CheckErr(err)
+
+	if dryRun {
+		// the payload is printed as JSON unless another output is given
+		if outputType == outputText {
+			outputType = outputJSON
+		}
+		printOutput(msg, nil)
+		return
+	}
+
+	out := postedOutput{Standup: strings.TrimSuffix(mostRecentStandup, ".md"), Message: msg}
+	printOutput(out, func() {
+		fmt.Printf("Posted standup %s\n", out.Standup)
+	})
+}
+
+// postedOutput is a posted standup printed with --output
+type postedOutput struct {
+	// Standup is the name of the standup that was posted
+	Standup string `json:"standup" yaml:"standup"`
+	// Message is the payload that was posted
+	Message slack.Message `json:"message" yaml:"message"`
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/markdown"
+	"github.com/rdark/standupnotes/internal/report"
+	"github.com/rdark/standupnotes/internal/util"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	reportWeek           string
+	reportBy             string
+	reportFrom           string
+	reportTo             string
+	reportSince          string
+	reportClientPrefixes []string
+	reportClients        []string
+)
+
+var weeklyReportCmd = &cobra.Command{
+	Use:   "weekly-report",
+	Short: "Summarise the work done across the journals of a week",
+	Long: `Summarise the work done across the journals of an ISO week (e.g 2024-W50)
+Repeated items are merged and items referencing the same issue are grouped
+together, annotated with the days they were worked on
+	`,
+	Run: weeklyReportCmdFunc,
+}
+
+func init() {
+	year, week := time.Now().ISOWeek()
+	weeklyReportCmd.PersistentFlags().StringVarP(&reportWeek, "week", "w", fmt.Sprintf("%04d-W%02d", year, week), "ISO week to report on")
+	weeklyReportCmd.PersistentFlags().StringSliceVar(&noteTags, "tag", []string{}, "Only include notes tagged with all of these front matter tags, e.g company:acme")
+	rootCmd.AddCommand(weeklyReportCmd)
+}
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarise the work done across the journals of a date range",
+	Long: `Summarise the work done across the journals of a date range, grouped by client
+or by issue (--by)
+
+Work done items are attributed to the clients marked within them with #client or
+@client, or else to the clients the journal is tagged with in its front matter.
+Front matter tags and markers name a client when they start with one of the
+report.client_tag_prefixes (default company: and client:), which are removed to
+give the client name, so the tag company:acme and the marker @company:acme both
+attribute to acme. Markers without a prefix such as #acme only name a client
+listed in report.clients (or --clients), so other hashtags and mentions are
+not taken for clients
+	`,
+	Run: reportCmdFunc,
+}
+
+func init() {
+	reportCmd.PersistentFlags().StringVar(&reportBy, "by", "client", "What to group the work done by: client or issue")
+	reportCmd.PersistentFlags().StringVar(&reportFrom, "from", "", "Start date of the range to report on")
+	reportCmd.PersistentFlags().StringVar(&reportTo, "to", "", "End date of the range to report on (default today)")
+	reportCmd.PersistentFlags().StringVar(&reportSince, "since", "7d", "Relative range to report on, e.g 7d, 2w, 1m")
+	reportCmd.PersistentFlags().StringSliceVar(&reportClientPrefixes, "client-tag-prefixes", []string{}, "Prefixes of the tags naming clients (default company: and client:)")
+	reportCmd.PersistentFlags().StringSliceVar(&reportClients, "clients", []string{}, "Client names which may be marked within items without a prefix, e.g #acme")
+	reportCmd.PersistentFlags().StringSliceVar(&noteTags, "tag", []string{}, "Only include notes tagged with all of these front matter tags, e.g company:acme")
+	reportCmd.MarkFlagsMutuallyExclusive("from", "since")
+	rootCmd.AddCommand(reportCmd)
+}
+
+func reportCmdFunc(cmd *cobra.Command, args []string) {
+	if len(reportClientPrefixes) == 0 {
+		reportClientPrefixes = viper.GetStringSlice("report.client_tag_prefixes")
+		if len(reportClientPrefixes) == 0 {
+			reportClientPrefixes = []string{"company:", "client:"}
+		}
+	}
+	if len(reportClients) == 0 {
+		reportClients = viper.GetStringSlice("report.clients")
+	}
+
+	since := reportSince
+	if reportFrom != "" {
+		since = ""
+	}
+	start, end, err := parseDateRange(reportFrom, reportTo, since)
+	cobra.CheckErr(err)
+
+	collector, err := collectJournalWorkDone(start, end, reportClients, reportClientPrefixes...)
+	cobra.CheckErr(err)
+
+	out := reportOutput{Start: start.Format("2006-01-02"), End: end.Format("2006-01-02")}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Work done %s - %s\n\n", out.Start, out.End)
+	switch reportBy {
+	case "client":
+		out.Clients, out.Other = collector.ByClient()
+		collector.WriteByClient(&sb, 2, "2006-01-02")
+	case "issue":
+		out.Issues, out.Other = collector.Grouped()
+		collector.WriteGrouped(&sb, 2, "2006-01-02")
+	default:
+		cobra.CheckErr(fmt.Errorf("unsupported --by %q: must be client or issue", reportBy))
+	}
+
+	printOutput(out, func() {
+		printMarkdown(sb.String())
+	})
+}
+
+// reportOutput is a summary of the work done across journals printed with
+// --output
+type reportOutput struct {
+	// Start date of the summary
+	Start string `json:"start" yaml:"start"`
+	// End date of the summary
+	End string `json:"end" yaml:"end"`
+	// Issues are the entries grouped by issue
+	Issues []report.IssueGroup `json:"issues,omitempty" yaml:"issues,omitempty"`
+	// Clients are the entries grouped by client
+	Clients []report.ClientGroup `json:"clients,omitempty" yaml:"clients,omitempty"`
+	// Other are the entries referencing no issue, or attributed to no client
+	Other []*report.Entry `json:"other" yaml:"other"`
+}
+
+func weeklyReportCmdFunc(cmd *cobra.Command, args []string) {
+	monday, err := util.ParseISOWeek(reportWeek)
+	cobra.CheckErr(err)
+	sunday := monday.AddDate(0, 0, 6)
+
+	collector, err := collectJournalWorkDone(monday, sunday, nil)
+	cobra.CheckErr(err)
+
+	out := reportOutput{Start: monday.Format("2006-01-02"), End: sunday.Format("2006-01-02")}
+	out.Issues, out.Other = collector.Grouped()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Week %s (%s - %s)\n\n", reportWeek, out.Start, out.End)
+	collector.WriteGrouped(&sb, 2, "Mon")
+
+	printOutput(out, func() {
+		printMarkdown(sb.String())
+	})
+}
+
+// collectJournalWorkDone collects the work done sections of the journals
+// dated from start to end inclusive, attributing them to the clients named by
+// the tags of each journal with any of clientPrefixes, or marked within them
+// with a prefix or one of clients
+func collectJournalWorkDone(start time.Time, end time.Time, clients []string, clientPrefixes ...string) (*report.Collector, error) {
+	journals, err := loadNotesInRange(journalDir, journalFilenameFormat, journalSkipText, markdown.NoteTypeJournal, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	matcher, err := newIssueMatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	collector := report.NewCollector(matcher, clientPrefixes...)
+	collector.Clients = clients
+	for _, journal := range journals {
+		clients := collector.ClientTags(journal.Content.FrontMatter.Tags)
+		for _, section := range journal.Content.Sections {
+			if containsFold(journalWorkDoneSections, section.Title) {
+				collector.Add(journal.Date, section.Content, clients...)
+			}
+		}
+	}
+
+	return collector, nil
+}
+
+// printMarkdown prints CommonMark content in the configured output format
+func printMarkdown(content string) {
+	renderer, err := newOutputRenderer(markdown.FormatCommonMark)
+	cobra.CheckErr(err)
+
+	parser := newParser(markdown.WithRenderer(renderer))
+	err = parser.RenderNote(os.Stdout, &markdown.NoteContent{Body: content}, nil)
+	cobra.CheckErr(err)
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/markdown"
+	"github.com/rdark/standupnotes/internal/util"
+
+	"github.com/spf13/cobra"
+)
+
+// carriedOverMarker replaces the checkbox of tasks rolled over into a later
+// note, so they are no longer open
+const carriedOverMarker = "[>]"
+
+var (
+	rolloverDate     string
+	rolloverNoteType string
+)
+
+var rolloverCmd = &cobra.Command{
+	Use:   "rollover",
+	Short: "Roll unfinished tasks over into today's note",
+	Long: `Roll the open tasks ("* [ ] ...") of the most recent note before the given day
+over into the note for the given day
+
+Open tasks are taken from the sections configured with rollover_sections (by
+default the goals of the day of journals and the work planned for today of
+standups) and appended to the rollover_target_section of the note for the day,
+skipping tasks it already holds. Nested tasks stay nested beneath the tasks
+rolled over with them, or are moved up to the top level. Each task is marked
+as carried over in the previous note with "[>]" and a link to the note it was
+carried over to`,
+	Run: rolloverCmdFunc,
+}
+
+func init() {
+	rolloverCmd.PersistentFlags().StringVarP(&rolloverDate, "date", "d", time.Now().Format("2006-01-02"), "Date of the note to roll tasks over into")
+	rolloverCmd.PersistentFlags().StringVarP(&rolloverNoteType, "type", "t", string(markdown.NoteTypeJournal), "Type of the notes to roll tasks over between")
+	rootCmd.AddCommand(rolloverCmd)
+}
+
+func rolloverCmdFunc(cmd *cobra.Command, args []string) {
+	dt, err := parseLocalDate(rolloverDate)
+	cobra.CheckErr(err)
+
+	noteType, err := findNoteType(rolloverNoteType)
+	cobra.CheckErr(err)
+
+	tasks, err := rolloverTasks(noteType, dt)
+	cobra.CheckErr(err)
+	if tasks == nil {
+		tasks = []markdown.Task{}
+	}
+	printOutput(tasks, func() {
+		printRolledOver(tasks)
+	})
+}
+
+// printRolledOver prints the tasks that were rolled over
+func printRolledOver(tasks []markdown.Task) {
+	for _, task := range tasks {
+		fmt.Printf("Rolled over: %s\n", task.Text)
+	}
+}
+
+// rolloverTasks appends the open tasks within the rollover sections of the most
+// recent note of noteType before dt to the rollover target section of the note
+// for dt, and marks them as carried over in the previous note. It returns the
+// tasks that were rolled over.
+func rolloverTasks(noteType *noteTypeConfig, dt time.Time) ([]markdown.Task, error) {
+	if len(noteType.RolloverSections) == 0 {
+		return nil, fmt.Errorf("no rollover_sections configured for %s notes", noteType.Type)
+	}
+
+	name := noteType.FilenameFormat.Format(dt)
+	content, err := noteWriter.ReadFile(filepath.Join(noteType.Dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("%s note for %s must exist to roll tasks over into: %w", noteType.Type, dt.Format("2006-01-02"), err)
+	}
+
+	previousName, err := util.GetMostRecentMdFileName(noteType.Dir, noteType.FilenameFormat, dt.AddDate(0, 0, -1))
+	if err != nil || previousName == "" {
+		return nil, err
+	}
+	previousContent, err := noteWriter.ReadFile(filepath.Join(noteType.Dir, previousName))
+	if err != nil {
+		return nil, err
+	}
+
+	parser := newParser()
+	previous, err := parser.ParseNoteContent(string(previousContent), noteType.SkipText, noteType.Type, markdown.WithNoteName(previousName))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", previousName, err)
+	}
+	md, err := parser.ParseNoteContent(string(content), noteType.SkipText, noteType.Type, markdown.WithNoteName(name))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	var tasks []markdown.Task
+	for _, task := range previous.Tasks {
+		if !task.Checked && containsFold(noteType.RolloverSections, task.Section) {
+			tasks = append(tasks, task)
+		}
+	}
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+
+	// tasks may already have been copied into the note. Nested tasks stay
+	// nested beneath the nearest task rolled over along with them, or are
+	// moved up to the top level.
+	var lines []string
+	// the depth each ancestor of an item was rolled over at, 0 if it was not
+	var depths []int
+	for _, item := range previous.Items {
+		for len(depths) < item.Depth {
+			depths = append(depths, 0)
+		}
+		depths = depths[:item.Depth]
+		depths[item.Depth-1] = 0
+
+		if !item.Task || item.Checked || !containsFold(noteType.RolloverSections, item.Section) ||
+			slices.ContainsFunc(md.Tasks, func(existing markdown.Task) bool {
+				return strings.EqualFold(existing.Text, item.Text)
+			}) {
+			continue
+		}
+
+		depth := 1
+		for _, ancestor := range depths[:item.Depth-1] {
+			if ancestor > 0 {
+				depth = ancestor + 1
+			}
+		}
+		depths[item.Depth-1] = depth
+		lines = append(lines, strings.Repeat("    ", depth-1)+"* [ ] "+item.Text)
+	}
+	if len(lines) > 0 {
+		content, err = markdown.AppendToSection(content, md, noteType.RolloverTarget, strings.Join(lines, "\n"))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if err := noteWriter.WriteFile(filepath.Join(noteType.Dir, name), content, 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	link := noteLink(previousName, strings.TrimSuffix(name, ".md"))
+	previousContent = markCarriedOver(previousContent, tasks, link)
+	if err := noteWriter.WriteFile(filepath.Join(noteType.Dir, previousName), previousContent, 0644); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// markCarriedOver replaces the checkbox of each task within content with
+// carriedOverMarker and appends a link to the note the task was carried over
+// to
+func markCarriedOver(content []byte, tasks []markdown.Task, link string) []byte {
+	// edit from the end of the note so earlier offsets remain valid
+	for i := len(tasks) - 1; i >= 0; i-- {
+		task := tasks[i]
+
+		lineEnd := len(content)
+		if end := bytes.IndexByte(content[task.Offset:], '\n'); end >= 0 {
+			lineEnd = task.Offset + end
+		}
+		backLink := " ([carried over](" + link + "))"
+
+		content = slices.Concat(
+			content[:task.Offset:task.Offset],
+			[]byte(carriedOverMarker),
+			content[task.Offset+len(carriedOverMarker):lineEnd],
+			[]byte(backLink),
+			content[lineEnd:],
+		)
+	}
+	return content
+}
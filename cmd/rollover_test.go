@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestRollover(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".standupnotes.yaml": fmt.Sprintf("journal:\n  dir: %s\nstandup:\n  dir: %s\n", filepath.Join(dir, "journal"), filepath.Join(dir, "standup")),
+		"journal/2024-12-11.md": `# Daily
+
+## Goals of the Day
+
+* [ ] Review PLA-77
+    * [ ] Reply to comments
+    * [x] Fix CI
+* [x] Deploy
+* Notes
+    * [ ] Nested beneath a note
+* [ ] Already rolled over
+
+## Worked On
+
+* [ ] Not a goal
+`,
+		// the target section is the last of the note
+		"journal/2024-12-12.md": "# Daily\n\n## Worked On\n\n## Goals of the Day\n\n* [ ] Already rolled over",
+	})
+
+	runCommand(t, filepath.Join(dir, ".standupnotes.yaml"), "rollover", "--date", "2024-12-12")
+
+	want := `# Daily
+
+## Worked On
+
+## Goals of the Day
+
+* [ ] Already rolled over
+* [ ] Review PLA-77
+    * [ ] Reply to comments
+* [ ] Nested beneath a note
+`
+	if got := readFile(t, dir, "journal/2024-12-12.md"); got != want {
+		t.Errorf("got note\n%s\nwant\n%s", got, want)
+	}
+
+	// only the checkbox is replaced, with a link appended to the line
+	want = `# Daily
+
+## Goals of the Day
+
+* [>] Review PLA-77 ([carried over](2024-12-12))
+    * [>] Reply to comments ([carried over](2024-12-12))
+    * [x] Fix CI
+* [x] Deploy
+* Notes
+    * [>] Nested beneath a note ([carried over](2024-12-12))
+* [>] Already rolled over ([carried over](2024-12-12))
+
+## Worked On
+
+* [ ] Not a goal
+`
+	if got := readFile(t, dir, "journal/2024-12-11.md"); got != want {
+		t.Errorf("got previous note\n%s\nwant\n%s", got, want)
+	}
+}
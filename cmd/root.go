@@ -3,21 +3,35 @@ package cmd
 import (
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/rdark/standupnotes/internal/markdown"
+	"github.com/rdark/standupnotes/internal/util"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	cfgFile                 string
-	journalDir              string
-	standupDir              string
-	journalWorkDoneSections []string
-	standupWorkDoneSection  string
-	standupSkipText         []string
-	journalSkipText         []string
+	cfgFile                   string
+	outputType                string
+	outputFormat              string
+	dryRun                    bool
+	backup                    bool
+	journalDir                string
+	standupDir                string
+	journalWorkDoneSections   []string
+	standupWorkDoneSection    string
+	standupTodaySection       string
+	journalGoalsSections      []string
+	journalCarryOverSections  []string
+	standupSkipText           []string
+	journalSkipText           []string
 	journalLinkPreviousTitles []string
-	journalLinkNextTitles []string
+	journalLinkNextTitles     []string
+	journalFilenameFormat     *util.FilenameFormat
+	standupFilenameFormat     *util.FilenameFormat
+	noteWriter                *util.FileWriter
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -27,6 +41,26 @@ var rootCmd = &cobra.Command{
 	Long:  `Generate standup notes from journal entries`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		var err error
+		if outputFormat == "" {
+			outputFormat = viper.GetString("format")
+		}
+		if outputType == "" {
+			outputType = viper.GetString("output")
+			if outputType == "" {
+				outputType = outputText
+			}
+		}
+		cobra.CheckErr(checkOutputType())
+		if !backup {
+			backup = viper.GetBool("backup")
+		}
+		// diffs of a dry run would corrupt JSON or YAML results
+		diffOut := os.Stdout
+		if structuredOutput() {
+			diffOut = os.Stderr
+		}
+		noteWriter = util.NewFileWriter(dryRun, backup, diffOut)
+
 		if journalDir == "" {
 			journalDir = viper.GetString("journal.dir")
 		}
@@ -39,12 +73,36 @@ var rootCmd = &cobra.Command{
 		standupDir, err = filepath.Abs(standupDir)
 		cobra.CheckErr(err)
 
+		journalFilenameFormat, err = util.ParseFilenameFormat(viper.GetString("journal.filename_format"))
+		cobra.CheckErr(err)
+		standupFilenameFormat, err = util.ParseFilenameFormat(viper.GetString("standup.filename_format"))
+		cobra.CheckErr(err)
+
 		if len(journalWorkDoneSections) == 0 {
 			journalWorkDoneSections = viper.GetStringSlice("journal.work_done_sections")
 		}
 		if standupWorkDoneSection == "" {
 			standupWorkDoneSection = viper.GetString("standup.work_done_section")
 		}
+		if standupTodaySection == "" {
+			standupTodaySection = viper.GetString("standup.today_section")
+			if standupTodaySection == "" {
+				standupTodaySection = "Working on Today"
+			}
+		}
+		if len(journalGoalsSections) == 0 {
+			journalGoalsSections = viper.GetStringSlice("journal.goals_sections")
+			if len(journalGoalsSections) == 0 {
+				journalGoalsSections = []string{"Goals of the Day"}
+			}
+		}
+
+		if len(journalCarryOverSections) == 0 {
+			journalCarryOverSections = viper.GetStringSlice("journal.carry_over_sections")
+			if len(journalCarryOverSections) == 0 {
+				journalCarryOverSections = []string{"Goals of the Day", "Goals of the Week"}
+			}
+		}
 
 		if len(journalSkipText) == 0 {
 			journalSkipText = viper.GetStringSlice("journal.skip_text")
@@ -66,6 +124,11 @@ var rootCmd = &cobra.Command{
 			}
 		}
 
+		cobra.CheckErr(loadNoteTypes())
+
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(saveNoteIndex())
 	},
 }
 
@@ -82,21 +145,49 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is .standupnotes.yaml)")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "print a diff of the changes to notes, or the payload to post, instead of making them")
+	rootCmd.PersistentFlags().BoolVar(&backup, "backup", false, "keep a timestamped .bak copy of notes before changing them")
+	rootCmd.PersistentFlags().StringVarP(&outputType, "output", "o", "", "output type of the results of commands: "+strings.Join(outputTypes, ", ")+" (default text)")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", "", "output format of printed sections: "+strings.Join(markdown.Formats, ", "))
 
 	rootCmd.PersistentFlags().StringVar(&journalDir, "journal-dir", "", "journal notes directory")
 	rootCmd.PersistentFlags().StringVar(&standupDir, "standup-dir", "", "standup notes directory")
 
 	rootCmd.PersistentFlags().StringSliceVar(&journalWorkDoneSections, "journal-work-done-sections", []string{}, "journal work done sections")
 	rootCmd.PersistentFlags().StringVar(&standupWorkDoneSection, "standup-work-done-section", "Worked on yesterday", "standup work done section")
+	rootCmd.PersistentFlags().StringVar(&standupTodaySection, "standup-today-section", "", "standup section for work planned today")
+	rootCmd.PersistentFlags().StringSliceVar(&journalCarryOverSections, "journal-carry-over-sections", []string{}, "journal sections copied from the previous journal into a new journal")
+	rootCmd.PersistentFlags().StringSliceVar(&journalGoalsSections, "journal-goals-sections", []string{}, "journal sections holding the goals for the day")
 
 	rootCmd.PersistentFlags().StringSliceVar(&standupSkipText, "standup-skip-text", []string{}, "Text lines to skip in standup notes")
 	rootCmd.PersistentFlags().StringSliceVar(&journalSkipText, "journal-skip-text", []string{}, "Text lines to skip in journal notes")
 
-	rootCmd.PersistentFlags().StringSliceVar(&standupSkipText, "journal-link-prevous-titles", []string{}, "A list of link titles to match within journal notes that should be references to the previous journal")
-	rootCmd.PersistentFlags().StringSliceVar(&journalSkipText, "journal-link-next-titles", []string{}, "A list of link titles to match within journal notes that should be references to the next journal")
+	rootCmd.PersistentFlags().StringSliceVar(&journalLinkPreviousTitles, "journal-link-prevous-titles", []string{}, "A list of link titles to match within journal notes that should be references to the previous journal")
+	rootCmd.PersistentFlags().StringSliceVar(&journalLinkNextTitles, "journal-link-next-titles", []string{}, "A list of link titles to match within journal notes that should be references to the next journal")
 
 }
 
+// newOutputRenderer returns the renderer for the configured output format, or
+// for defaultFormat when no format is configured
+func newOutputRenderer(defaultFormat string) (markdown.Renderer, error) {
+	if outputFormat == "" {
+		return markdown.NewRenderer(defaultFormat)
+	}
+	return markdown.NewRenderer(outputFormat)
+}
+
+// newParser returns a parser for the registered note types
+func newParser(opts ...markdown.ParserOption) *markdown.Parser {
+	var noteTypeOpts []markdown.ParserOption
+	for _, noteType := range noteTypes {
+		noteTypeOpts = append(noteTypeOpts,
+			markdown.WithNoteDir(noteType.Type, noteType.Dir),
+			markdown.WithFilenameFormat(noteType.Type, noteType.FilenameFormat),
+		)
+	}
+	return markdown.NewParser(append(noteTypeOpts, opts...)...)
+}
+
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 	if cfgFile != "" {
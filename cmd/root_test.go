@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// writeFiles writes files, keyed by their path relative to dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// readFile returns the content of the file at the path relative to dir
+func readFile(t *testing.T, dir string, name string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+// resetFlags sets the flags of cmd and its subcommands back to their defaults
+func resetFlags(t *testing.T, cmd *cobra.Command) {
+	t.Helper()
+	for _, flags := range []*pflag.FlagSet{cmd.Flags(), cmd.PersistentFlags()} {
+		flags.VisitAll(func(flag *pflag.Flag) {
+			var err error
+			if value, ok := flag.Value.(pflag.SliceValue); ok {
+				var defaults []string
+				if def := strings.Trim(flag.DefValue, "[]"); def != "" {
+					defaults = strings.Split(def, ",")
+				}
+				err = value.Replace(defaults)
+			} else {
+				err = flag.Value.Set(flag.DefValue)
+			}
+			if err != nil {
+				t.Fatalf("resetting --%s: %v", flag.Name, err)
+			}
+			flag.Changed = false
+		})
+	}
+	for _, sub := range cmd.Commands() {
+		resetFlags(t, sub)
+	}
+}
+
+// runCommand runs rootCmd with args and the config file config, returning
+// what it printed to stdout. The flags and configuration of previous runs
+// are reset first, as they are held by package variables.
+func runCommand(t *testing.T, config string, args ...string) string {
+	t.Helper()
+
+	resetFlags(t, rootCmd)
+	viper.Reset()
+	cfgFile = config
+	outputType, outputFormat = "", ""
+	journalDir, standupDir = "", ""
+	journalWorkDoneSections, standupWorkDoneSection, standupTodaySection = nil, "", ""
+	journalGoalsSections, journalCarryOverSections = nil, nil
+	journalSkipText, standupSkipText = nil, nil
+	journalLinkPreviousTitles, journalLinkNextTitles = nil, nil
+	createJournalCmd, createStandupCmd = "", ""
+	journalTemplatePath, standupTemplatePath = "", ""
+	journalRollover = false
+	indexDir, noteIndex = "", nil
+	noteTags = nil
+
+	stdout := os.Stdout
+	out, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = out
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	rootCmd.SetArgs(append([]string{"--config", config}, args...))
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("%v: %v", args, err)
+	}
+
+	if _, err := out.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	printed, err := io.ReadAll(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(printed)
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/markdown"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchSections  []string
+	searchNoteTypes []string
+	searchFrom      string
+	searchTo        string
+	searchSince     string
+	searchTask      string
+	searchLimit     int
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search [terms...]",
+	Short: "Search the list items of every journal and standup",
+	Long: `Search the list items of every journal and standup for the given terms, newest
+first, e.g to find when SSO was last worked on:
+
+  standupnotes search sso --section "Worked On" -n 1
+
+Items match when they contain every term, ignoring case. Without terms every
+item matching the other filters is listed. Results are scoped with --section,
+--type, --tag, --from/--to or --since, and --task (open, done, or any for every
+task list item), and printed as "date path:line: [section] text", or as JSON
+or YAML with --output`,
+	Run: searchCmdFunc,
+}
+
+func init() {
+	searchCmd.PersistentFlags().StringSliceVarP(&searchSections, "section", "s", []string{}, "Only search the sections with these titles")
+	searchCmd.PersistentFlags().StringSliceVarP(&searchNoteTypes, "type", "t", []string{string(markdown.NoteTypeJournal), string(markdown.NoteTypeStandup)}, "Types of the notes to search")
+	searchCmd.PersistentFlags().StringVar(&searchFrom, "from", "", "Start date of the range to search")
+	searchCmd.PersistentFlags().StringVar(&searchTo, "to", "", "End date of the range to search (default today)")
+	searchCmd.PersistentFlags().StringVar(&searchSince, "since", "", "Relative range to search, e.g 7d, 2w, 1m (default all notes)")
+	searchCmd.PersistentFlags().StringVar(&searchTask, "task", "", "Only search task list items: open, done or any")
+	searchCmd.PersistentFlags().IntVarP(&searchLimit, "limit", "n", 0, "Maximum number of results (default all)")
+	searchCmd.PersistentFlags().StringSliceVar(&noteTags, "tag", []string{}, "Only include notes tagged with all of these front matter tags, e.g company:acme")
+	searchCmd.MarkFlagsMutuallyExclusive("from", "since")
+	rootCmd.AddCommand(searchCmd)
+}
+
+// searchResult is a list item matching a search
+type searchResult struct {
+	// Date of the note holding the item
+	Date string `json:"date" yaml:"date"`
+	// Type of the note holding the item
+	Type markdown.NoteType `json:"type" yaml:"type"`
+	// Path of the note holding the item
+	Path string `json:"path" yaml:"path"`
+	// Line of the item within the note, starting at 1
+	Line int `json:"line" yaml:"line"`
+	// Section holding the item
+	Section string `json:"section" yaml:"section"`
+	// Text of the item
+	Text string `json:"text" yaml:"text"`
+	// Task is true for task list items
+	Task bool `json:"task" yaml:"task"`
+	// Checked is true for completed task list items
+	Checked bool `json:"checked" yaml:"checked"`
+
+	// date orders the results
+	date time.Time
+}
+
+func searchCmdFunc(cmd *cobra.Command, args []string) {
+	switch searchTask {
+	case "", "open", "done", "any":
+	default:
+		cobra.CheckErr(fmt.Errorf("unsupported --task %q: must be open, done or any", searchTask))
+	}
+
+	end := time.Now()
+	var start time.Time
+	if searchFrom != "" || searchTo != "" || searchSince != "" {
+		var err error
+		start, end, err = parseDateRange(searchFrom, searchTo, searchSince)
+		cobra.CheckErr(err)
+	}
+
+	terms := make([]string, 0, len(args))
+	for _, arg := range args {
+		terms = append(terms, strings.Fields(strings.ToLower(arg))...)
+	}
+
+	results := make([]searchResult, 0)
+	for _, name := range searchNoteTypes {
+		noteType, err := findNoteType(name)
+		cobra.CheckErr(err)
+
+		notes, err := loadNotesInRange(noteType.Dir, noteType.FilenameFormat, noteType.SkipText, noteType.Type, start, end)
+		cobra.CheckErr(err)
+
+		for _, note := range notes {
+			for _, item := range note.Content.Items {
+				if !matchesSearch(item, terms) {
+					continue
+				}
+				results = append(results, searchResult{
+					Date:    note.Date.Format("2006-01-02"),
+					Type:    noteType.Type,
+					Path:    displayPath(filepath.Join(noteType.Dir, filepath.FromSlash(note.Name))),
+					Line:    item.Line,
+					Section: item.Section,
+					Text:    item.Text,
+					Task:    item.Task,
+					Checked: item.Checked,
+					date:    note.Date,
+				})
+			}
+		}
+	}
+
+	// newest first, keeping the order of items within a note
+	slices.SortStableFunc(results, func(a, b searchResult) int {
+		return b.date.Compare(a.date)
+	})
+	if searchLimit > 0 && len(results) > searchLimit {
+		results = results[:searchLimit]
+	}
+
+	printOutput(results, func() {
+		for _, result := range results {
+			fmt.Printf("%s %s:%d: [%s] %s\n", result.Date, result.Path, result.Line, result.Section, result.Text)
+		}
+	})
+}
+
+// matchesSearch reports whether the item matches the --section and --task
+// flags and contains every one of the lower cased terms
+func matchesSearch(item markdown.ListItem, terms []string) bool {
+	if len(searchSections) > 0 && !containsFold(searchSections, item.Section) {
+		return false
+	}
+
+	switch searchTask {
+	case "open":
+		if !item.Task || item.Checked {
+			return false
+		}
+	case "done":
+		if !item.Task || !item.Checked {
+			return false
+		}
+	case "any":
+		if !item.Task {
+			return false
+		}
+	}
+
+	text := strings.ToLower(item.Text)
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".standupnotes.yaml":    fmt.Sprintf("journal:\n  dir: %s\nstandup:\n  dir: %s\n", filepath.Join(dir, "journal"), filepath.Join(dir, "standup")),
+		"journal/2024-12-10.md": "---\ntags: [company:acme]\n---\n\n# Daily\n\n## Goals of the Day\n\n* [ ] Fix SSO login\n* [x] Deploy caching\n\n## Worked On\n\n* Looked into SSO timeouts\n",
+		"journal/2024-12-11.md": "# Daily\n\n## Worked On\n\n* SSO for globex\n* Reviewed PLA-77\n",
+		"standup/2024-12-11.md": "# Standup\n\n## Today\n\n* Pair on sso\n",
+	})
+	config := filepath.Join(dir, ".standupnotes.yaml")
+
+	tests := []struct {
+		name string
+		args []string
+		// texts of the items found, in order
+		want []string
+	}{
+		{name: "term", args: []string{"sso"}, want: []string{"SSO for globex", "Pair on sso", "Fix SSO login", "Looked into SSO timeouts"}},
+		{name: "every term", args: []string{"sso login"}, want: []string{"Fix SSO login"}},
+		{name: "no terms", args: []string{"--type", "standup"}, want: []string{"Pair on sso"}},
+		{name: "section", args: []string{"sso", "--section", "worked on"}, want: []string{"SSO for globex", "Looked into SSO timeouts"}},
+		{name: "open tasks", args: []string{"--task", "open"}, want: []string{"Fix SSO login"}},
+		{name: "done tasks", args: []string{"--task", "done"}, want: []string{"Deploy caching"}},
+		{name: "any tasks", args: []string{"--task", "any"}, want: []string{"Fix SSO login", "Deploy caching"}},
+		{name: "range", args: []string{"sso", "--from", "2024-12-11", "--to", "2024-12-11"}, want: []string{"SSO for globex", "Pair on sso"}},
+		{name: "tag", args: []string{"sso", "--tag", "company:acme"}, want: []string{"Fix SSO login", "Looked into SSO timeouts"}},
+		{name: "limit", args: []string{"sso", "-n", "1"}, want: []string{"SSO for globex"}},
+		{name: "no match", args: []string{"kubernetes"}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := runCommand(t, config, append([]string{"search", "--output", "json"}, tt.args...)...)
+
+			var results []searchResult
+			if err := json.Unmarshal([]byte(out), &results); err != nil {
+				t.Fatalf("%v: %s", err, out)
+			}
+			got := make([]string, 0)
+			for _, result := range results {
+				got = append(got, result.Text)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	out := runCommand(t, config, "search", "timeouts")
+	want := fmt.Sprintf("2024-12-10 %s:14: [Worked On] Looked into SSO timeouts\n", filepath.Join(dir, "journal", "2024-12-10.md"))
+	if out != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+}
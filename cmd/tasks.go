@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/markdown"
+	"github.com/rdark/standupnotes/internal/util"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	tasksOpen      bool
+	tasksDone      bool
+	tasksSince     string
+	tasksNoteTypes []string
+)
+
+var tasksCmd = &cobra.Command{
+	Use:   "tasks",
+	Short: "List the tasks within journals and standups",
+	Long: `List the task list items ("* [ ] ..." and "* [x] ...") within journal and
+standup notes, under a header per note
+
+Use --open or --done to only list open or completed tasks, --since to only list
+tasks from recent notes and --type to list tasks from other note types`,
+	Run: tasksCmdFunc,
+}
+
+func init() {
+	tasksCmd.PersistentFlags().BoolVar(&tasksOpen, "open", false, "Only list open tasks")
+	tasksCmd.PersistentFlags().BoolVar(&tasksDone, "done", false, "Only list completed tasks")
+	tasksCmd.PersistentFlags().StringVar(&tasksSince, "since", "", "Relative range to list tasks for, e.g 7d, 2w, 1m (default all notes)")
+	tasksCmd.PersistentFlags().StringSliceVarP(&tasksNoteTypes, "type", "t", []string{string(markdown.NoteTypeJournal), string(markdown.NoteTypeStandup)}, "Types of the notes to list tasks from")
+	tasksCmd.MarkFlagsMutuallyExclusive("open", "done")
+	tasksCmd.PersistentFlags().StringSliceVar(&noteTags, "tag", []string{}, "Only include notes tagged with all of these front matter tags, e.g company:acme")
+	rootCmd.AddCommand(tasksCmd)
+}
+
+func tasksCmdFunc(cmd *cobra.Command, args []string) {
+	end := time.Now()
+	var start time.Time
+	if tasksSince != "" {
+		var err error
+		start, err = util.ParseSince(tasksSince, end)
+		cobra.CheckErr(err)
+	}
+
+	var sb strings.Builder
+	listed := make([]markdown.Task, 0)
+	for _, name := range tasksNoteTypes {
+		noteType, err := findNoteType(name)
+		cobra.CheckErr(err)
+
+		notes, err := loadNotesInRange(noteType.Dir, noteType.FilenameFormat, noteType.SkipText, noteType.Type, start, end)
+		cobra.CheckErr(err)
+
+		for _, note := range notes {
+			tasks := filterTasks(note.Content.Tasks)
+			if len(tasks) == 0 {
+				continue
+			}
+			listed = append(listed, tasks...)
+
+			fmt.Fprintf(&sb, "## %s %s\n\n", noteType.Type, note.Date.Format("2006-01-02"))
+			for _, task := range tasks {
+				checkbox := "[ ]"
+				if task.Checked {
+					checkbox = "[x]"
+				}
+				fmt.Fprintf(&sb, "* %s %s\n", checkbox, task.Text)
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	printOutput(listed, func() {
+		printMarkdown(sb.String())
+	})
+}
+
+// filterTasks returns the tasks matching the --open and --done flags
+func filterTasks(tasks []markdown.Task) []markdown.Task {
+	var filtered []markdown.Task
+	for _, task := range tasks {
+		if tasksOpen && task.Checked || tasksDone && !task.Checked {
+			continue
+		}
+		filtered = append(filtered, task)
+	}
+	return filtered
+}
@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/markdown"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// defaultWatchDebounce is how long the journal must be unchanged for before
+// the standup is synced
+const defaultWatchDebounce = 500 * time.Millisecond
+
+// standupTodayRegion is the name of the region of the standup holding the work
+// planned for today, as delimited in the built-in standup template
+const standupTodayRegion = "today"
+
+// errNoTodayRegion is returned for standups without markers delimiting the
+// work planned for today
+var errNoTodayRegion = errors.New("no today region")
+
+var (
+	watchDebounce time.Duration
+	watchSections []string
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Keep today's standup in sync with today's journal",
+	Long: `Watch the journal directory and whenever today's journal changes, replace the
+work planned for today (standup.today_section) in today's standup with the goals
+of the day from the journal, as generate-standup does
+
+Only the lines between the markers of the today region of that section are
+rewritten, as included by the built-in standup template:
+
+  <!-- standup:begin today -->
+  <!-- standup:end today -->
+
+Everything else in the standup, including edits made by hand, is left as is.
+Standups without the markers are skipped with a warning; add them around the
+work planned for today to have it synced.
+
+The standup is synced once the journal has been unchanged for --debounce
+(watch.debounce), so saving several times in quick succession writes the
+standup once. Other journal sections are synced with --sections
+(watch.sections), e.g to list what has been worked on so far today:
+
+  standupnotes watch --sections "Worked On"
+
+The standup is not created when it does not exist yet, run generate-standup
+first. Stop watching with Ctrl-C`,
+	Run: watchCmdFunc,
+}
+
+func init() {
+	watchCmd.PersistentFlags().DurationVar(&watchDebounce, "debounce", 0, "How long the journal must be unchanged for before syncing the standup (default 500ms)")
+	watchCmd.PersistentFlags().StringSliceVar(&watchSections, "sections", []string{}, "Journal sections synced into the standup (default the journal goals sections)")
+	rootCmd.AddCommand(watchCmd)
+}
+
+// syncedOutput is a standup synced with the journal printed with --output
+type syncedOutput struct {
+	// Journal is the path of the journal synced from
+	Journal string `json:"journal" yaml:"journal"`
+	// Standup is the path of the standup synced to
+	Standup string `json:"standup" yaml:"standup"`
+	// Section is the title of the standup section rewritten
+	Section string `json:"section" yaml:"section"`
+}
+
+func watchCmdFunc(cmd *cobra.Command, args []string) {
+	if watchDebounce == 0 {
+		watchDebounce = viper.GetDuration("watch.debounce")
+		if watchDebounce <= 0 {
+			watchDebounce = defaultWatchDebounce
+		}
+	}
+	if len(watchSections) == 0 {
+		watchSections = viper.GetStringSlice("watch.sections")
+		if len(watchSections) == 0 {
+			watchSections = journalGoalsSections
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watcher, err := fsnotify.NewWatcher()
+	cobra.CheckErr(err)
+	defer watcher.Close()
+
+	cobra.CheckErr(watchJournalDirs(watcher, time.Now()))
+	syncStandup(time.Now())
+
+	watchJournal(ctx, watcher, watcher.Events, watcher.Errors, syncStandup)
+}
+
+// dirWatcher adds directories to watch, as fsnotify.Watcher does
+type dirWatcher interface {
+	Add(name string) error
+}
+
+// watchJournal calls sync once the journal for today has been unchanged for
+// watchDebounce after events for it, until ctx is done or events is closed.
+// Directories created leading to the journal are added to watcher.
+func watchJournal(ctx context.Context, watcher dirWatcher, events <-chan fsnotify.Event, errs <-chan error, sync func(time.Time)) {
+	// the timer only fires once events have stopped for the debounce
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			now := time.Now()
+			// the journal, or a directory leading to it, may be created by the
+			// event, e.g on the first day of the month
+			if event.Has(fsnotify.Create) {
+				if err := watchJournalDirs(watcher, now); err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to watch journal: %v\n", err)
+				}
+			}
+			if filepath.Clean(event.Name) == journalPath(now) {
+				debounce.Reset(watchDebounce)
+			}
+		case err, ok := <-errs:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(os.Stderr, "Failed to watch journal: %v\n", err)
+		case <-debounce.C:
+			sync(time.Now())
+		}
+	}
+}
+
+// journalPath returns the path of the journal for dt
+func journalPath(dt time.Time) string {
+	return filepath.Join(journalDir, filepath.FromSlash(journalFilenameFormat.Format(dt)))
+}
+
+// watchJournalDirs watches the journal directory along with each existing
+// directory leading to the journal for dt, for filename formats nesting
+// journals in directories. Editors commonly save by renaming a new file over
+// the note, so directories are watched rather than the journal itself.
+func watchJournalDirs(watcher dirWatcher, dt time.Time) error {
+	for dir := filepath.Dir(journalPath(dt)); ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(dir); err == nil {
+			if err := watcher.Add(dir); err != nil {
+				return err
+			}
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		if dir == journalDir || dir == filepath.Dir(dir) {
+			return nil
+		}
+	}
+}
+
+// syncStandup syncs the standup for dt with the journal for dt, printing the
+// standup when it changed. Failures are printed so watching carries on.
+func syncStandup(dt time.Time) {
+	out, changed, err := syncStandupToday(dt)
+	if errors.Is(err, errNoTodayRegion) {
+		fmt.Fprintf(os.Stderr, "Warning: skipping %s: add %q and %q around the work planned for today to sync it\n",
+			out.Standup, "<!-- standup:begin "+standupTodayRegion+" -->", "<!-- standup:end "+standupTodayRegion+" -->")
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to sync standup: %v\n", err)
+		return
+	}
+	if !changed {
+		return
+	}
+
+	printOutput(out, func() {
+		fmt.Printf("Synced %s into %s\n", out.Journal, out.Standup)
+	})
+}
+
+// syncStandupToday replaces the content of the today region of the standup for
+// dt with the content of the synced sections of the journal for dt, reporting
+// whether the standup changed. errNoTodayRegion is returned for standups
+// without the region.
+func syncStandupToday(dt time.Time) (syncedOutput, bool, error) {
+	journalName := journalFilenameFormat.Format(dt)
+	standupName := standupFilenameFormat.Format(dt)
+	out := syncedOutput{
+		Journal: journalPath(dt),
+		Standup: filepath.Join(standupDir, filepath.FromSlash(standupName)),
+		Section: standupTodaySection,
+	}
+
+	today, err := journalSectionsContent(journalName, watchSections)
+	if errors.Is(err, fs.ErrNotExist) {
+		// nothing to sync until today's journal is created
+		return out, false, nil
+	}
+	if err != nil {
+		return out, false, err
+	}
+
+	content, err := noteWriter.ReadFile(out.Standup)
+	if errors.Is(err, fs.ErrNotExist) {
+		return out, false, fmt.Errorf("no standup for %s, run generate-standup to create it", dt.Format("2006-01-02"))
+	}
+	if err != nil {
+		return out, false, err
+	}
+
+	parser := newParser()
+	md, err := parser.ParseNoteContent(string(content), standupSkipText, markdown.NoteTypeStandup, markdown.WithNoteName(standupName))
+	if err != nil {
+		return out, false, fmt.Errorf("%s: %w", out.Standup, err)
+	}
+
+	if md.MarkerError != nil {
+		return out, false, fmt.Errorf("%s: %w", out.Standup, md.MarkerError)
+	}
+	// only the lines between the markers are ever rewritten
+	if _, ok := markdown.FindRegion(md, standupTodayRegion); !ok {
+		return out, false, errNoTodayRegion
+	}
+	updated, err := markdown.ReplaceRegion(content, md, standupTodayRegion, today)
+	if err != nil {
+		return out, false, fmt.Errorf("%s: %w", out.Standup, err)
+	}
+	if bytes.Equal(updated, content) {
+		return out, false, nil
+	}
+
+	return out, true, noteWriter.WriteFile(out.Standup, updated, 0644)
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// stubWatcher records the directories added to it
+type stubWatcher struct {
+	added []string
+}
+
+func (w *stubWatcher) Add(name string) error {
+	w.added = append(w.added, name)
+	return nil
+}
+
+func TestWatchJournal(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".standupnotes.yaml": fmt.Sprintf("journal:\n  dir: %s\n  filename_format: \"%%Y/%%m/%%Y-%%m-%%d\"\nstandup:\n  dir: %s\n",
+			filepath.Join(dir, "journal"), filepath.Join(dir, "standup")),
+		"journal/.keep": "",
+	})
+	runCommand(t, filepath.Join(dir, ".standupnotes.yaml"), "tasks")
+	watchDebounce = 100 * time.Millisecond
+
+	now := time.Now()
+	journal := journalPath(now)
+	events := make(chan fsnotify.Event)
+	synced := make(chan time.Time, 8)
+	watcher := &stubWatcher{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watchJournal(context.Background(), watcher, events, nil, func(dt time.Time) {
+			synced <- dt
+		})
+	}()
+
+	// expectSyncs fails unless count syncs happen within a few debounces
+	expectSyncs := func(count int) []time.Time {
+		t.Helper()
+		var syncs []time.Time
+		timeout := time.After(5 * watchDebounce)
+		for {
+			select {
+			case dt := <-synced:
+				syncs = append(syncs, dt)
+			case <-timeout:
+				if len(syncs) != count {
+					t.Fatalf("got %d syncs, want %d", len(syncs), count)
+				}
+				return syncs
+			}
+		}
+	}
+
+	// other notes are ignored
+	events <- fsnotify.Event{Name: journalPath(now.AddDate(0, 0, -1)), Op: fsnotify.Write}
+	events <- fsnotify.Event{Name: filepath.Join(dir, "standup", filepath.Base(journal)), Op: fsnotify.Write}
+	expectSyncs(0)
+
+	// the directories leading to the journal are watched once created
+	if err := os.MkdirAll(filepath.Dir(journal), 0755); err != nil {
+		t.Fatal(err)
+	}
+	events <- fsnotify.Event{Name: filepath.Dir(filepath.Dir(journal)), Op: fsnotify.Create}
+
+	// saving several times in quick succession syncs once
+	var last time.Time
+	for range 3 {
+		last = time.Now()
+		events <- fsnotify.Event{Name: journal, Op: fsnotify.Write}
+		time.Sleep(watchDebounce / 4)
+	}
+	if syncs := expectSyncs(1); syncs[0].Sub(last) < watchDebounce {
+		t.Errorf("synced %s after the last event, want at least %s", syncs[0].Sub(last), watchDebounce)
+	}
+
+	// editors saving by renaming a new file over the journal
+	events <- fsnotify.Event{Name: filepath.Join(filepath.Dir(journal), ".", filepath.Base(journal)), Op: fsnotify.Create}
+	expectSyncs(1)
+
+	close(events)
+	<-done
+	for _, want := range []string{filepath.Dir(journal), filepath.Dir(filepath.Dir(journal))} {
+		if !slices.Contains(watcher.added, want) {
+			t.Errorf("%s was not watched, watched %v", want, watcher.added)
+		}
+	}
+}
+
+func TestSyncStandupToday(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".standupnotes.yaml": fmt.Sprintf("journal:\n  dir: %s\nstandup:\n  dir: %s\n", filepath.Join(dir, "journal"), filepath.Join(dir, "standup")),
+	})
+	runCommand(t, filepath.Join(dir, ".standupnotes.yaml"), "tasks")
+	watchSections = []string{"Goals of the Day"}
+
+	now := time.Now()
+	name := now.Format("2006-01-02") + ".md"
+	writeFiles(t, dir, map[string]string{
+		"journal/" + name: "# Daily\n\n## Goals of the Day\n\n* [ ] Review PLA-77\n* [ ] Deploy\n\n## Worked On\n\n* Caching\n",
+	})
+
+	tests := []struct {
+		name    string
+		standup string
+		// standup after syncing, empty when unchanged
+		want string
+		err  bool
+		// errIs is wrapped by the error returned, when known
+		errIs error
+	}{
+		{
+			name:    "region",
+			standup: "# Standup\n\n## Today\n\nEdited by hand\n\n<!-- standup:begin today -->\n* [ ] Review PLA-77\n<!-- standup:end today -->\n\n## Blocked on\n",
+			want:    "# Standup\n\n## Today\n\nEdited by hand\n\n<!-- standup:begin today -->\n* [ ] Review PLA-77\n* [ ] Deploy\n<!-- standup:end today -->\n\n## Blocked on\n",
+		},
+		{
+			name:    "in sync",
+			standup: "<!-- standup:begin today -->\n* [ ] Review PLA-77\n* [ ] Deploy\n<!-- standup:end today -->\n",
+		},
+		{
+			name:    "no region",
+			standup: "# Standup\n\n## Today\n\n* old\n",
+			err:     true,
+			errIs:   errNoTodayRegion,
+		},
+		{
+			name:    "invalid marker",
+			standup: "<!-- standup:begin today -->\n* old\n",
+			err:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeFiles(t, dir, map[string]string{"standup/" + name: tt.standup})
+
+			_, changed, err := syncStandupToday(now)
+			if tt.err != (err != nil) {
+				t.Fatalf("got error %v, want an error %v", err, tt.err)
+			}
+			if tt.errIs != nil && !errors.Is(err, tt.errIs) {
+				t.Errorf("got error %v, want %v", err, tt.errIs)
+			}
+
+			want := tt.want
+			if want == "" {
+				want = tt.standup
+			}
+			if changed != (want != tt.standup) {
+				t.Errorf("got changed %v", changed)
+			}
+			if got := readFile(t, dir, "standup/"+name); got != want {
+				t.Errorf("got standup\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
@@ -8,13 +8,18 @@ import (
 	"time"
 
 	"github.com/rdark/standupnotes/internal/markdown"
+	"github.com/rdark/standupnotes/internal/report"
 	"github.com/rdark/standupnotes/internal/util"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	date string
+	date          string
+	workDoneFrom  string
+	workDoneTo    string
+	workDoneSince string
+	workDoneMerge bool
 )
 
 var journalWorkDoneCmd = &cobra.Command{
@@ -23,46 +28,130 @@ var journalWorkDoneCmd = &cobra.Command{
 	Long: `Export work done from the journal for a given day
 If a journal note does not exist for the given day, the journal directory will
 be searched backwards for the newest journal within 30 days of the given date
+
+With --from/--to or --since, work done is exported from every journal in the
+range under a header per day, or as a single deduplicated list with --merge.
+With --tag, only the work done of journals tagged with the given front matter
+tags is exported
 	`,
 	Run: journalWorkDoneCmdFunc,
 }
 
 func init() {
 	journalWorkDoneCmd.PersistentFlags().StringVarP(&date, "date", "d", time.Now().AddDate(0, 0, -1).Format("2006-01-02"), "Date to print work done for")
+	journalWorkDoneCmd.PersistentFlags().StringVar(&workDoneFrom, "from", "", "Start date of a range to print work done for")
+	journalWorkDoneCmd.PersistentFlags().StringVar(&workDoneTo, "to", "", "End date of a range to print work done for (default today)")
+	journalWorkDoneCmd.PersistentFlags().StringVar(&workDoneSince, "since", "", "Relative range to print work done for, e.g 7d, 2w, 1m")
+	journalWorkDoneCmd.PersistentFlags().BoolVar(&workDoneMerge, "merge", false, "Merge the work done across the range into a single deduplicated list")
+	journalWorkDoneCmd.PersistentFlags().StringSliceVar(&noteTags, "tag", []string{}, "Only include notes tagged with all of these front matter tags, e.g company:acme")
+	journalWorkDoneCmd.MarkFlagsMutuallyExclusive("from", "since")
 	rootCmd.AddCommand(journalWorkDoneCmd)
 }
 
 func journalWorkDoneCmdFunc(cmd *cobra.Command, args []string) {
-	dt, err := time.Parse("2006-01-02", date)
+	if workDoneFrom != "" || workDoneTo != "" || workDoneSince != "" {
+		journalWorkDoneRange()
+		return
+	}
+
+	dt, err := parseLocalDate(date)
 	cobra.CheckErr(err)
 
-	mostRecentJournal, err := util.GetMostRecentMdFileName(journalDir, dt)
+	mostRecentJournal, err := util.GetMostRecentMdFileName(journalDir, journalFilenameFormat, dt)
 	cobra.CheckErr(err)
 
 	content, err := os.ReadFile(path.Join(journalDir, mostRecentJournal))
 	cobra.CheckErr(err)
 
-	parser := markdown.NewParser()
-	md, err := parser.ParseNoteContent(string(content), journalSkipText, markdown.NoteTypeJournal)
+	renderer, err := newOutputRenderer(markdown.FormatCommonMark)
+	cobra.CheckErr(err)
+
+	parser := newParser(markdown.WithRenderer(renderer))
+	md, err := parser.ParseNoteContent(string(content), journalSkipText, markdown.NoteTypeJournal, markdown.WithNoteName(mostRecentJournal))
 	cobra.CheckErr(err)
 
+	out := workDoneOutput{Note: mostRecentJournal, Sections: make([]markdown.Section, 0)}
+	if dt, ok := journalFilenameFormat.Parse(mostRecentJournal); ok {
+		out.Date = dt.Format("2006-01-02")
+	}
+	// the work done of a journal missing any of the tags is left out, as it is
+	// from ranges
 	for _, section := range md.Sections {
-		for _, cfgSection := range journalWorkDoneSections {
-			if strings.EqualFold(section.Title, cfgSection) {
-				fmt.Printf("### %s\n", section.Title)
-				fmt.Println(section.Content)
-			}
+		if containsFold(journalWorkDoneSections, section.Title) && hasTags(md, noteTags) {
+			out.Sections = append(out.Sections, section)
+		}
+	}
+
+	printOutput(out, func() {
+		for _, section := range out.Sections {
+			fmt.Println(renderer.Heading(3, section.Title))
+			fmt.Println(section.Content)
+		}
+	})
+}
+
+// workDoneOutput is the work done of a note printed with --output
+type workDoneOutput struct {
+	// Date of the note
+	Date string `json:"date" yaml:"date"`
+	// Note is the name of the note
+	Note string `json:"note" yaml:"note"`
+	// Sections holding the work done
+	Sections []markdown.Section `json:"sections" yaml:"sections"`
+}
+
+// journalWorkDoneRange prints the work done from every journal in the range
+// given by --from/--to or --since
+func journalWorkDoneRange() {
+	start, end, err := parseDateRange(workDoneFrom, workDoneTo, workDoneSince)
+	cobra.CheckErr(err)
+
+	if workDoneMerge {
+		collector, err := collectJournalWorkDone(start, end, nil)
+		cobra.CheckErr(err)
+
+		entries := collector.Entries()
+		if entries == nil {
+			entries = []*report.Entry{}
 		}
+		var sb strings.Builder
+		report.WriteList(&sb, entries, "")
+		printOutput(entries, func() {
+			printMarkdown(sb.String())
+		})
+		return
 	}
-	for _, link := range md.AdjacentLinks {
-		fmt.Printf("Source Note Type: %d\n", link.SourceNoteType)
-		fmt.Printf("Target Note Type: %d\n", link.TargetNoteType)
-		fmt.Printf("Link Title: %s\n", link.Title)
-		fmt.Printf("Link Target: %s\n", link.Target)
-		fmt.Printf("Link Start: %d\n", link.LinkStart)
-		fmt.Printf("Link End: %d\n", link.LinkEnd)
-		fmt.Println(md.Body[link.LinkStart:link.LinkEnd])
+
+	journals, err := loadNotesInRange(journalDir, journalFilenameFormat, journalSkipText, markdown.NoteTypeJournal, start, end)
+	cobra.CheckErr(err)
+
+	out := make([]workDoneOutput, 0, len(journals))
+	for _, journal := range journals {
+		workDone := workDoneOutput{Date: journal.Date.Format("2006-01-02"), Note: journal.Name, Sections: make([]markdown.Section, 0)}
+		for _, section := range journal.Content.Sections {
+			if containsFold(journalWorkDoneSections, section.Title) && section.Content != "" {
+				workDone.Sections = append(workDone.Sections, section)
+			}
+		}
+		out = append(out, workDone)
 	}
+
+	renderer, err := newOutputRenderer(markdown.FormatCommonMark)
+	cobra.CheckErr(err)
+
+	// the sections of the notes are loaded as CommonMark, so are rendered
+	// again in the output format
+	parser := newParser(markdown.WithRenderer(renderer))
+	printOutput(out, func() {
+		for _, workDone := range out {
+			fmt.Println(renderer.Heading(2, workDone.Date))
+			fmt.Println()
+			for _, section := range workDone.Sections {
+				fmt.Println(renderer.Heading(3, section.Title))
+				cobra.CheckErr(parser.RenderNote(os.Stdout, &markdown.NoteContent{Body: section.Content}, nil))
+			}
+		}
+	})
 }
 
 var standupWorkDoneCmd = &cobra.Command{
@@ -81,25 +170,36 @@ func init() {
 }
 
 func standupWorkDoneCmdFunc(cmd *cobra.Command, args []string) {
-	dt, err := time.Parse("2006-01-02", date)
+	dt, err := parseLocalDate(date)
 	cobra.CheckErr(err)
 
-	mostRecentStandup, err := util.GetMostRecentMdFileName(standupDir, dt)
+	mostRecentStandup, err := util.GetMostRecentMdFileName(standupDir, standupFilenameFormat, dt)
 	cobra.CheckErr(err)
 
 	content, err := os.ReadFile(path.Join(standupDir, mostRecentStandup))
 	cobra.CheckErr(err)
 
-	parser := markdown.NewParser()
-	md, err := parser.ParseNoteContent(string(content), standupSkipText, markdown.NoteTypeStandup)
+	renderer, err := newOutputRenderer(markdown.FormatCommonMark)
 	cobra.CheckErr(err)
 
+	parser := newParser(markdown.WithRenderer(renderer))
+	md, err := parser.ParseNoteContent(string(content), standupSkipText, markdown.NoteTypeStandup, markdown.WithNoteName(mostRecentStandup))
+	cobra.CheckErr(err)
+
+	out := workDoneOutput{Note: mostRecentStandup, Sections: make([]markdown.Section, 0)}
+	if dt, ok := standupFilenameFormat.Parse(mostRecentStandup); ok {
+		out.Date = dt.Format("2006-01-02")
+	}
 	for _, section := range md.Sections {
 		if strings.EqualFold(section.Title, standupWorkDoneSection) {
-			fmt.Printf("### %s\n", section.Title)
-			fmt.Println(section.Content)
-			// fmt.Println("here")
-			// fmt.Println(string(md.Body[section.ContentStart:section.ContentEnd]))
+			out.Sections = append(out.Sections, section)
 		}
 	}
+
+	printOutput(out, func() {
+		for _, section := range out.Sections {
+			fmt.Println(renderer.Heading(3, section.Title))
+			fmt.Println(section.Content)
+		}
+	})
 }
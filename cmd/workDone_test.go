@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJournalWorkDoneTag(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".standupnotes.yaml":    fmt.Sprintf("journal:\n  dir: %s\n  work_done_sections: [worked on]\nstandup:\n  dir: %s\n", filepath.Join(dir, "journal"), filepath.Join(dir, "standup")),
+		"journal/2024-12-11.md": "---\ntags: [company:acme]\n---\n# Daily\n\n## Worked On\n\n* SSO for acme\n",
+		"journal/2024-12-12.md": "---\ntags: [company:globex]\n---\n# Daily\n\n## Worked On\n\n* Caching for globex\n",
+	})
+	config := filepath.Join(dir, ".standupnotes.yaml")
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"--date", "2024-12-12"}, want: "Caching for globex"},
+		{args: []string{"--date", "2024-12-12", "--tag", "company:globex"}, want: "Caching for globex"},
+		{args: []string{"--date", "2024-12-12", "--tag", "company:acme"}},
+		{args: []string{"--from", "2024-12-01", "--to", "2024-12-31", "--tag", "company:acme"}, want: "SSO for acme"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			out := runCommand(t, config, append([]string{"journal-work-done", "--output", "json"}, tt.args...)...)
+			for _, text := range []string{"SSO for acme", "Caching for globex"} {
+				if strings.Contains(out, text) != (text == tt.want) {
+					t.Errorf("got output\n%s\nwant only %q", out, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Package index caches parsed notes on disk so they are only parsed again
+// once they change.
+package index
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/markdown"
+	"github.com/rdark/standupnotes/internal/util"
+)
+
+// version of the index format, bumped whenever what is parsed from notes
+// changes; indexes of other versions are discarded
+const version = 3
+
+// FileName is the name of the index file within its directory
+const FileName = "index.gob"
+
+func init() {
+	// front matter values decoded from YAML
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+	gob.Register(time.Time{})
+}
+
+// entry is a parsed note along with what it was parsed from
+type entry struct {
+	// ModTime and Size of the file when it was parsed
+	ModTime time.Time
+	Size    int64
+	// Hash of the content of the file
+	Hash [sha256.Size]byte
+	// Key identifies the configuration the note was parsed with
+	Key string
+	// Content is the parsed note
+	Content *markdown.NoteContent
+}
+
+// file is the content of the index file
+type file struct {
+	Version int
+	Entries map[string]*entry
+}
+
+// Index holds parsed notes keyed by their path. It is safe for concurrent use.
+type Index struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	dirty   bool
+}
+
+// Open returns the index stored in dir, or an empty index when there is none
+// or it cannot be read
+func Open(dir string) (*Index, error) {
+	ix := &Index{path: filepath.Join(dir, FileName), entries: make(map[string]*entry)}
+
+	content, err := os.ReadFile(ix.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ix, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// an unreadable index is rebuilt as notes are parsed
+	var f file
+	if err := gob.NewDecoder(bytes.NewReader(content)).Decode(&f); err != nil || f.Version != version || f.Entries == nil {
+		ix.dirty = true
+		return ix, nil
+	}
+	ix.entries = f.Entries
+	return ix, nil
+}
+
+// Parse returns the parsed content of the note at path, parsing it with parse
+// unless the index holds the note unchanged since it was parsed with the same
+// key. The key identifies everything other than the content of the note that
+// the result of parse depends on.
+func (ix *Index) Parse(path string, key string, parse func(content []byte) (*markdown.NoteContent, error)) (*markdown.NoteContent, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ix.mu.Lock()
+	cached, ok := ix.entries[path]
+	ix.mu.Unlock()
+	if ok && cached.Key == key && cached.Size == info.Size() && cached.ModTime.Equal(info.ModTime()) {
+		return cached.Content, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(content)
+
+	// touched but unchanged
+	if ok && cached.Key == key && cached.Hash == hash {
+		ix.put(path, &entry{ModTime: info.ModTime(), Size: info.Size(), Hash: hash, Key: key, Content: cached.Content})
+		return cached.Content, nil
+	}
+
+	md, err := parse(content)
+	if err != nil {
+		return nil, err
+	}
+	ix.put(path, &entry{ModTime: info.ModTime(), Size: info.Size(), Hash: hash, Key: key, Content: md})
+	return md, nil
+}
+
+// put stores the entry for path
+func (ix *Index) put(path string, e *entry) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.entries[path] = e
+	ix.dirty = true
+}
+
+// Len returns the number of notes held by the index
+func (ix *Index) Len() int {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return len(ix.entries)
+}
+
+// Clear removes every note from the index
+func (ix *Index) Clear() {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.entries = make(map[string]*entry)
+	ix.dirty = true
+}
+
+// Save writes the index to disk if it has changed, dropping the notes that no
+// longer exist
+func (ix *Index) Save() error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	for path := range ix.entries {
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			delete(ix.entries, path)
+			ix.dirty = true
+		}
+	}
+	if !ix.dirty {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(file{Version: version, Entries: ix.entries}); err != nil {
+		return err
+	}
+	// the index is a cache, so is written even during a dry run
+	if err := util.NewFileWriter(false, false, nil).WriteFile(ix.path, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	ix.dirty = false
+	return nil
+}
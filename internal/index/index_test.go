@@ -0,0 +1,154 @@
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/markdown"
+)
+
+// counter is a parse function returning the content of notes as their body,
+// counting the notes it parses
+type counter struct {
+	parsed int
+}
+
+func (c *counter) parse(content []byte) (*markdown.NoteContent, error) {
+	c.parsed++
+	return &markdown.NoteContent{Body: string(content)}, nil
+}
+
+// writeNote writes content to path with the given modification time
+func writeNote(t *testing.T, path string, content string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "2024-12-12.md")
+	modTime := time.Date(2024, 12, 12, 9, 0, 0, 0, time.UTC)
+	writeNote(t, path, "# Daily", modTime)
+
+	ix, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c counter
+
+	steps := []struct {
+		name string
+		// change made to the note before it is parsed
+		change func()
+		key    string
+		parsed int
+		body   string
+	}{
+		{name: "new", key: "a", parsed: 1, body: "# Daily"},
+		{name: "unchanged", key: "a", parsed: 1, body: "# Daily"},
+		{name: "touched", change: func() { writeNote(t, path, "# Daily", modTime.Add(time.Hour)) }, key: "a", parsed: 1, body: "# Daily"},
+		{name: "same size", change: func() { writeNote(t, path, "# DAILY", modTime.Add(2*time.Hour)) }, key: "a", parsed: 2, body: "# DAILY"},
+		{name: "size", change: func() { writeNote(t, path, "# Daily log", modTime.Add(2*time.Hour)) }, key: "a", parsed: 3, body: "# Daily log"},
+		{name: "key", key: "b", parsed: 4, body: "# Daily log"},
+	}
+	for _, step := range steps {
+		if step.change != nil {
+			step.change()
+		}
+		md, err := ix.Parse(path, step.key, c.parse)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.parsed != step.parsed || md.Body != step.body {
+			t.Errorf("%s: parsed %d times with body %q, want %d times with body %q", step.name, c.parsed, md.Body, step.parsed, step.body)
+		}
+	}
+}
+
+func TestSave(t *testing.T) {
+	dir := t.TempDir()
+	modTime := time.Date(2024, 12, 12, 9, 0, 0, 0, time.UTC)
+	kept := filepath.Join(dir, "2024-12-11.md")
+	deleted := filepath.Join(dir, "2024-12-12.md")
+	writeNote(t, kept, "# Kept", modTime)
+	writeNote(t, deleted, "# Deleted", modTime)
+
+	ix, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c counter
+	for _, path := range []string{kept, deleted} {
+		if _, err := ix.Parse(path, "a", c.parse); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ix.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Remove(deleted); err != nil {
+		t.Fatal(err)
+	}
+	ix, err = Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ix.Parse(kept, "a", c.parse); err != nil {
+		t.Fatal(err)
+	}
+	if c.parsed != 2 {
+		t.Errorf("parsed %d times, want the saved index to be used", c.parsed)
+	}
+	if err := ix.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	ix, err = Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ix.Len() != 1 {
+		t.Errorf("got %d notes, want the deleted note dropped", ix.Len())
+	}
+}
+
+func TestOpenCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "2024-12-12.md")
+	writeNote(t, path, "# Daily", time.Date(2024, 12, 12, 9, 0, 0, 0, time.UTC))
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte("not a gob"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ix, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c counter
+	if _, err := ix.Parse(path, "a", c.parse); err != nil {
+		t.Fatal(err)
+	}
+	if err := ix.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the corrupt index is replaced
+	ix, err = Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ix.Parse(path, "a", c.parse); err != nil {
+		t.Fatal(err)
+	}
+	if c.parsed != 1 {
+		t.Errorf("parsed %d times, want the rebuilt index to be used", c.parsed)
+	}
+}
@@ -0,0 +1,240 @@
+package issues
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Details are the details of an issue held by its tracker
+type Details struct {
+	// Title of the issue
+	Title string
+	// Status of the issue, such as In Progress or closed
+	Status string
+}
+
+// Client fetches the details of issues from a tracker
+type Client interface {
+	// Fetch returns the details of the issue with the given key
+	Fetch(ctx context.Context, key string) (*Details, error)
+}
+
+// Enrich sets the title and status of each issue from the client for its
+// tracker, using the client for defaultTracker for issues whose tracker is not
+// known. Issues without a client are left alone. The errors fetching issues
+// are returned together after every issue has been tried.
+func Enrich(ctx context.Context, issues []*Issue, clients map[string]Client, defaultTracker string) error {
+	var errs []error
+	for _, issue := range issues {
+		tracker := issue.Tracker
+		if tracker == "" {
+			tracker = defaultTracker
+		}
+		client, ok := clients[tracker]
+		if !ok {
+			continue
+		}
+
+		details, err := client.Fetch(ctx, issue.Key)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", issue.Key, err))
+			continue
+		}
+		issue.Title = details.Title
+		issue.Status = details.Status
+	}
+	return errors.Join(errs...)
+}
+
+// httpClient sends authenticated JSON requests to the API of a tracker
+type httpClient struct {
+	// BaseURL of the API, replaceable to point at another server
+	BaseURL string
+	// Token authenticating requests
+	Token string
+	// HTTPClient sends the requests
+	HTTPClient *http.Client
+
+	// authorization returns the Authorization header for the token
+	authorization func(token string) string
+}
+
+// do sends a request with an optional JSON body to path below the base URL and
+// decodes the JSON response into v
+func (c *httpClient) do(ctx context.Context, method string, path string, body interface{}, v interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.BaseURL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", c.authorization(c.Token))
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("request failed: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// LinearClient fetches issues from the Linear GraphQL API
+type LinearClient struct {
+	httpClient
+}
+
+// NewLinearClient returns a LinearClient for the API at baseURL (default
+// https://api.linear.app) authenticated with an API key
+func NewLinearClient(baseURL string, token string) *LinearClient {
+	if baseURL == "" {
+		baseURL = "https://api.linear.app"
+	}
+	return &LinearClient{httpClient{
+		BaseURL:       baseURL,
+		Token:         token,
+		HTTPClient:    &http.Client{Timeout: 30 * time.Second},
+		authorization: func(token string) string { return token },
+	}}
+}
+
+// Fetch returns the details of the issue with the given key, such as PLA-77
+func (c *LinearClient) Fetch(ctx context.Context, key string) (*Details, error) {
+	query := map[string]interface{}{
+		"query":     `query($id: String!) { issue(id: $id) { title state { name } } }`,
+		"variables": map[string]string{"id": key},
+	}
+
+	var resp struct {
+		Data struct {
+			Issue *struct {
+				Title string `json:"title"`
+				State struct {
+					Name string `json:"name"`
+				} `json:"state"`
+			} `json:"issue"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/graphql", query, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("linear: %s", resp.Errors[0].Message)
+	}
+	if resp.Data.Issue == nil {
+		return nil, fmt.Errorf("linear: issue not found")
+	}
+
+	return &Details{Title: resp.Data.Issue.Title, Status: resp.Data.Issue.State.Name}, nil
+}
+
+// JiraClient fetches issues from the Jira REST API
+type JiraClient struct {
+	httpClient
+}
+
+// NewJiraClient returns a JiraClient for the site at baseURL (e.g
+// https://example.atlassian.net) authenticated with a bearer token, or with
+// basic auth when the token is given as email:api-token
+func NewJiraClient(baseURL string, token string) *JiraClient {
+	return &JiraClient{httpClient{
+		BaseURL:    baseURL,
+		Token:      token,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		authorization: func(token string) string {
+			if user, password, ok := strings.Cut(token, ":"); ok {
+				req := http.Request{Header: http.Header{}}
+				req.SetBasicAuth(user, password)
+				return req.Header.Get("Authorization")
+			}
+			return "Bearer " + token
+		},
+	}}
+}
+
+// Fetch returns the details of the issue with the given key, such as ABC-12
+func (c *JiraClient) Fetch(ctx context.Context, key string) (*Details, error) {
+	var resp struct {
+		Fields struct {
+			Summary string `json:"summary"`
+			Status  struct {
+				Name string `json:"name"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "?fields=summary,status"
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &Details{Title: resp.Fields.Summary, Status: resp.Fields.Status.Name}, nil
+}
+
+// GitHubClient fetches issues and pull requests from the GitHub REST API
+type GitHubClient struct {
+	httpClient
+}
+
+// NewGitHubClient returns a GitHubClient for the API at baseURL (default
+// https://api.github.com) authenticated with a token
+func NewGitHubClient(baseURL string, token string) *GitHubClient {
+	if baseURL == "" {
+		baseURL = "https://api.github.com"
+	}
+	return &GitHubClient{httpClient{
+		BaseURL:       baseURL,
+		Token:         token,
+		HTTPClient:    &http.Client{Timeout: 30 * time.Second},
+		authorization: func(token string) string { return "Bearer " + token },
+	}}
+}
+
+// Fetch returns the details of the issue with the given key, such as
+// org/repo#123
+func (c *GitHubClient) Fetch(ctx context.Context, key string) (*Details, error) {
+	repo, number, ok := strings.Cut(key, "#")
+	if !ok {
+		return nil, fmt.Errorf("github: %q is not an org/repo#number reference", key)
+	}
+
+	var resp struct {
+		Title string `json:"title"`
+		State string `json:"state"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/repos/"+repo+"/issues/"+url.PathEscape(number), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &Details{Title: resp.Title, Status: resp.State}, nil
+}
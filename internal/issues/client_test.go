@@ -0,0 +1,176 @@
+package issues
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestServer returns the URL of a server handling requests with handler
+func newTestServer(t *testing.T, handler http.HandlerFunc) string {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestLinearClient(t *testing.T) {
+	url := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/graphql" {
+			t.Errorf("got %s %s, want POST /graphql", r.Method, r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "lin_api_key" {
+			t.Errorf("Authorization = %q, want the API key", auth)
+		}
+
+		var body struct {
+			Variables map[string]string `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding query: %v", err)
+		}
+		if body.Variables["id"] == "PLA-404" {
+			_, _ = w.Write([]byte(`{"data":{"issue":null},"errors":[{"message":"Entity not found"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"issue":{"title":"Webhooks","state":{"name":"In Progress"}}}}`))
+	})
+	client := NewLinearClient(url, "lin_api_key")
+
+	details, err := client.Fetch(context.Background(), "PLA-77")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *details != (Details{Title: "Webhooks", Status: "In Progress"}) {
+		t.Errorf("Fetch() = %+v", details)
+	}
+
+	if _, err := client.Fetch(context.Background(), "PLA-404"); err == nil || !strings.Contains(err.Error(), "Entity not found") {
+		t.Errorf("got error %v, want the GraphQL error", err)
+	}
+}
+
+func TestJiraClient(t *testing.T) {
+	url := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/issue/OPS-12" || r.URL.Query().Get("fields") != "summary,status" {
+			t.Errorf("got request for %s", r.URL)
+		}
+		if user, password, ok := r.BasicAuth(); !ok || user != "me@example.com" || password != "secret" {
+			t.Errorf("got Authorization %q, want basic auth", r.Header.Get("Authorization"))
+		}
+		_, _ = w.Write([]byte(`{"fields":{"summary":"Rotate keys","status":{"name":"Done"}}}`))
+	})
+
+	details, err := NewJiraClient(url, "me@example.com:secret").Fetch(context.Background(), "OPS-12")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *details != (Details{Title: "Rotate keys", Status: "Done"}) {
+		t.Errorf("Fetch() = %+v", details)
+	}
+}
+
+func TestJiraClientBearerToken(t *testing.T) {
+	url := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer pat" {
+			t.Errorf("Authorization = %q, want a bearer token", auth)
+		}
+		_, _ = w.Write([]byte(`{"fields":{}}`))
+	})
+
+	if _, err := NewJiraClient(url, "pat").Fetch(context.Background(), "OPS-12"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGitHubClient(t *testing.T) {
+	url := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/api/issues/12" {
+			t.Errorf("got request for %s", r.URL)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer ghp_token" {
+			t.Errorf("Authorization = %q, want a bearer token", auth)
+		}
+		_, _ = w.Write([]byte(`{"title":"Add SSO","state":"closed"}`))
+	})
+	client := NewGitHubClient(url, "ghp_token")
+
+	details, err := client.Fetch(context.Background(), "acme/api#12")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *details != (Details{Title: "Add SSO", Status: "closed"}) {
+		t.Errorf("Fetch() = %+v", details)
+	}
+
+	if _, err := client.Fetch(context.Background(), "PLA-77"); err == nil {
+		t.Error("expected an error for a key that is not a GitHub reference")
+	}
+}
+
+func TestClientErrorStatus(t *testing.T) {
+	url := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Not Found", http.StatusNotFound)
+	})
+
+	_, err := NewGitHubClient(url, "").Fetch(context.Background(), "acme/api#404")
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("got error %v, want the status", err)
+	}
+}
+
+// fakeClient returns the details keyed by issue key, failing for other keys
+type fakeClient map[string]Details
+
+func (c fakeClient) Fetch(ctx context.Context, key string) (*Details, error) {
+	details, ok := c[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &details, nil
+}
+
+func TestEnrich(t *testing.T) {
+	issues := []*Issue{
+		{Reference: Reference{Key: "PLA-77", Tracker: TrackerLinear}},
+		{Reference: Reference{Key: "PLA-78"}},
+		{Reference: Reference{Key: "acme/api#1", Tracker: TrackerGitHub}},
+		{Reference: Reference{Key: "OPS-1", Tracker: TrackerJira}},
+		{Reference: Reference{Key: "PLA-404"}},
+	}
+	clients := map[string]Client{
+		TrackerLinear: fakeClient{"PLA-77": {Title: "Webhooks", Status: "Todo"}, "PLA-78": {Title: "SSO", Status: "Done"}},
+		TrackerGitHub: fakeClient{"acme/api#1": {Title: "Fix CI", Status: "open"}},
+	}
+
+	err := Enrich(context.Background(), issues, clients, TrackerLinear)
+	// the failure is reported once every other issue is enriched
+	if err == nil || !strings.Contains(err.Error(), "PLA-404: not found") {
+		t.Errorf("got error %v, want the failure of PLA-404", err)
+	}
+
+	want := []Details{{"Webhooks", "Todo"}, {"SSO", "Done"}, {"Fix CI", "open"}, {}, {}}
+	for i, issue := range issues {
+		if got := (Details{Title: issue.Title, Status: issue.Status}); got != want[i] {
+			t.Errorf("%s enriched with %+v, want %+v", issue.Key, got, want[i])
+		}
+	}
+}
+
+func TestEnrichCancelled(t *testing.T) {
+	url := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"title":"Fix CI","state":"open"}`))
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	issues := []*Issue{{Reference: Reference{Key: "acme/api#1", Tracker: TrackerGitHub}}}
+	err := Enrich(ctx, issues, map[string]Client{TrackerGitHub: NewGitHubClient(url, "")}, TrackerLinear)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
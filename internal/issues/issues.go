@@ -0,0 +1,217 @@
+// Package issues extracts references to issue trackers from notes and
+// enriches them with details fetched from the trackers.
+package issues
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Trackers of issues recognised by the default patterns
+const (
+	TrackerLinear = "linear"
+	TrackerJira   = "jira"
+	TrackerGitHub = "github"
+)
+
+// PatternConfig configures a pattern matching references to issues
+type PatternConfig struct {
+	// Tracker the matched issues belong to, empty when it is not known from
+	// the match alone
+	Tracker string `mapstructure:"tracker"`
+	// Pattern is the regular expression matching references
+	Pattern string `mapstructure:"pattern"`
+	// Key expands the match into the key of the issue, using ${name} to refer
+	// to named groups (default the group named key, or the whole match)
+	Key string `mapstructure:"key"`
+}
+
+// DefaultPatterns match Linear, Jira and GitHub issue URLs and GitHub
+// org/repo#123 references. Bare keys such as PLA-77 are too easily confused with
+// text such as UTF-8, so are only matched for known projects with
+// ProjectKeyPattern.
+var DefaultPatterns = []PatternConfig{
+	{Tracker: TrackerLinear, Pattern: `https?://linear\.app/[^/\s]+/issue/(?P<key>[A-Z][A-Z0-9]+-\d+)`},
+	{Tracker: TrackerJira, Pattern: `https?://[\w.-]+\.atlassian\.net/browse/(?P<key>[A-Z][A-Z0-9]+-\d+)`},
+	{Tracker: TrackerGitHub, Pattern: `https?://github\.com/(?P<repo>[\w.-]+/[\w.-]+)/(?:issues|pull)/(?P<number>\d+)`, Key: "${repo}#${number}"},
+	// only at the start of a word, so fragments of URLs are not matched
+	{Tracker: TrackerGitHub, Pattern: `(?:^|[\s(\[])(?P<repo>[\w.-]+/[\w.-]+)#(?P<number>\d+)\b`, Key: "${repo}#${number}"},
+}
+
+// ProjectKeyPattern returns a pattern matching bare keys of issues of the
+// projects with the given prefixes, e.g PLA-77 for the project PLA. The
+// tracker of the matched issues is not known.
+func ProjectKeyPattern(projects ...string) PatternConfig {
+	quoted := make([]string, 0, len(projects))
+	for _, project := range projects {
+		quoted = append(quoted, regexp.QuoteMeta(project))
+	}
+	return PatternConfig{Pattern: `\b(?:` + strings.Join(quoted, "|") + `)-\d+\b`}
+}
+
+// Reference is a reference to an issue found within text
+type Reference struct {
+	// Key of the issue, such as PLA-77 or org/repo#123
+	Key string `json:"key" yaml:"key"`
+	// Tracker of the issue, empty when not known
+	Tracker string `json:"tracker,omitempty" yaml:"tracker,omitempty"`
+	// URL of the issue, when referenced by URL
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+// pattern is a compiled PatternConfig
+type pattern struct {
+	tracker string
+	regexp  *regexp.Regexp
+	key     string
+}
+
+// Matcher finds references to issues within text
+type Matcher struct {
+	patterns []pattern
+}
+
+// NewMatcher returns a Matcher for the patterns, tried in order
+func NewMatcher(configs []PatternConfig) (*Matcher, error) {
+	m := &Matcher{}
+	for _, config := range configs {
+		re, err := regexp.Compile(config.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("issue pattern %q: %w", config.Pattern, err)
+		}
+
+		key := config.Key
+		if key == "" {
+			key = "$0"
+			if re.SubexpIndex("key") >= 0 {
+				key = "${key}"
+			}
+		}
+		m.patterns = append(m.patterns, pattern{tracker: config.Tracker, regexp: re, key: key})
+	}
+	return m, nil
+}
+
+// DefaultMatcher returns a Matcher for the DefaultPatterns
+func DefaultMatcher() *Matcher {
+	m, err := NewMatcher(DefaultPatterns)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Find returns the issues referenced within text, in the order they are first
+// referenced. An issue referenced both by URL and key is returned once, with
+// the tracker and URL given by the URL.
+func (m *Matcher) Find(text string) []Reference {
+	type match struct {
+		start int
+		ref   Reference
+	}
+
+	var matches []match
+	index := make(map[string]int)
+	for _, p := range m.patterns {
+		for _, loc := range p.regexp.FindAllStringSubmatchIndex(text, -1) {
+			key := string(p.regexp.ExpandString(nil, p.key, text, loc))
+			if key == "" {
+				continue
+			}
+
+			ref := Reference{Key: key, Tracker: p.tracker}
+			if p.tracker != "" && isURL(text[loc[0]:loc[1]]) {
+				ref.URL = text[loc[0]:loc[1]]
+			}
+
+			if i, ok := index[key]; ok {
+				existing := &matches[i]
+				existing.start = min(existing.start, loc[0])
+				if existing.ref.Tracker == "" {
+					existing.ref.Tracker = ref.Tracker
+				}
+				if existing.ref.URL == "" {
+					existing.ref.URL = ref.URL
+				}
+				continue
+			}
+			index[key] = len(matches)
+			matches = append(matches, match{start: loc[0], ref: ref})
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b match) int {
+		return a.start - b.start
+	})
+	refs := make([]Reference, 0, len(matches))
+	for _, match := range matches {
+		refs = append(refs, match.ref)
+	}
+	return refs
+}
+
+// FindKeys returns the keys of the issues referenced within text
+func (m *Matcher) FindKeys(text string) []string {
+	var keys []string
+	for _, ref := range m.Find(text) {
+		keys = append(keys, ref.Key)
+	}
+	return keys
+}
+
+// isURL reports whether s is an http or https URL
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// Issue is an issue worked on across notes
+type Issue struct {
+	Reference `yaml:",inline"`
+	// Dates of the notes the issue was referenced in
+	Dates []time.Time `json:"dates" yaml:"dates"`
+	// Title of the issue, when enriched
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+	// Status of the issue, when enriched
+	Status string `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// Collector accumulates the issues referenced across notes
+type Collector struct {
+	matcher *Matcher
+	issues  []*Issue
+	index   map[string]*Issue
+}
+
+// NewCollector returns an empty Collector finding issues with matcher
+func NewCollector(matcher *Matcher) *Collector {
+	return &Collector{matcher: matcher, index: make(map[string]*Issue)}
+}
+
+// Add collects the issues referenced within content of the note dated date
+func (c *Collector) Add(date time.Time, content string) {
+	for _, ref := range c.matcher.Find(content) {
+		issue, ok := c.index[ref.Key]
+		if !ok {
+			issue = &Issue{Reference: ref}
+			c.index[ref.Key] = issue
+			c.issues = append(c.issues, issue)
+		}
+		if issue.Tracker == "" {
+			issue.Tracker = ref.Tracker
+		}
+		if issue.URL == "" {
+			issue.URL = ref.URL
+		}
+		if !slices.ContainsFunc(issue.Dates, date.Equal) {
+			issue.Dates = append(issue.Dates, date)
+		}
+	}
+}
+
+// Issues returns the collected issues in the order they were first referenced
+func (c *Collector) Issues() []*Issue {
+	return c.issues
+}
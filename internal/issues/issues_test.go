@@ -0,0 +1,110 @@
+package issues
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name     string
+		projects []string
+		text     string
+		want     []Reference
+	}{
+		{
+			name: "linear url",
+			text: "* [PLA-77](https://linear.app/acme/issue/PLA-77) - webhooks",
+			want: []Reference{{Key: "PLA-77", Tracker: TrackerLinear, URL: "https://linear.app/acme/issue/PLA-77"}},
+		},
+		{
+			name: "jira url",
+			text: "see https://acme.atlassian.net/browse/OPS-12",
+			want: []Reference{{Key: "OPS-12", Tracker: TrackerJira, URL: "https://acme.atlassian.net/browse/OPS-12"}},
+		},
+		{
+			name: "github url and reference",
+			text: "merged https://github.com/acme/api/pull/12, then acme/api#13 and (acme/web#4)",
+			want: []Reference{
+				{Key: "acme/api#12", Tracker: TrackerGitHub, URL: "https://github.com/acme/api/pull/12"},
+				{Key: "acme/api#13", Tracker: TrackerGitHub},
+				{Key: "acme/web#4", Tracker: TrackerGitHub},
+			},
+		},
+		{
+			name: "url fragments",
+			text: "read https://example.com/owner/page#123 and example.com/docs/page#2",
+			want: []Reference{},
+		},
+		{
+			name: "bare keys without projects",
+			text: "PLA-77: UTF-8, SHA-256 and ISO-8601",
+			want: []Reference{},
+		},
+		{
+			name:     "bare keys of projects",
+			projects: []string{"PLA", "OPS"},
+			text:     "PLA-77 and OPS-1: UTF-8, SHA-256, XPLA-1 and PLA-77 again",
+			want:     []Reference{{Key: "PLA-77"}, {Key: "OPS-1"}},
+		},
+		{
+			name:     "bare key along with its url",
+			projects: []string{"PLA"},
+			text:     "PLA-77 is https://linear.app/acme/issue/PLA-77",
+			want:     []Reference{{Key: "PLA-77", Tracker: TrackerLinear, URL: "https://linear.app/acme/issue/PLA-77"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patterns := DefaultPatterns
+			if len(tt.projects) > 0 {
+				patterns = append(patterns[:len(patterns):len(patterns)], ProjectKeyPattern(tt.projects...))
+			}
+			m, err := NewMatcher(patterns)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := m.Find(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Find(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewMatcherKey(t *testing.T) {
+	m, err := NewMatcher([]PatternConfig{{Tracker: "gitlab", Pattern: `(?P<group>\w+)!(?P<mr>\d+)`, Key: "${group}/MR${mr}"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.FindKeys("reviewed infra!42"); !reflect.DeepEqual(got, []string{"infra/MR42"}) {
+		t.Errorf("FindKeys() = %v", got)
+	}
+
+	if _, err := NewMatcher([]PatternConfig{{Pattern: `(`}}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+func TestCollector(t *testing.T) {
+	m, err := NewMatcher(append(DefaultPatterns[:len(DefaultPatterns):len(DefaultPatterns)], ProjectKeyPattern("PLA")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	day1 := time.Date(2024, 12, 11, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	c := NewCollector(m)
+	c.Add(day1, "* PLA-77 webhooks\n* acme/api#1")
+	c.Add(day2, "* [PLA-77](https://linear.app/acme/issue/PLA-77) webhooks\n* PLA-77 again")
+
+	want := []*Issue{
+		{Reference: Reference{Key: "PLA-77", Tracker: TrackerLinear, URL: "https://linear.app/acme/issue/PLA-77"}, Dates: []time.Time{day1, day2}},
+		{Reference: Reference{Key: "acme/api#1", Tracker: TrackerGitHub}, Dates: []time.Time{day1}},
+	}
+	if got := c.Issues(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Issues() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,123 @@
+// Package lint reports problems found within notes.
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/markdown"
+)
+
+// Severity of an issue
+type Severity string
+
+// Severities of issues; only errors fail a lint
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a problem found within a note
+type Issue struct {
+	// File the issue was found in
+	File string `json:"file" yaml:"file"`
+	// Line of the issue, starting at 1
+	Line int `json:"line" yaml:"line"`
+	// Severity of the issue
+	Severity Severity `json:"severity" yaml:"severity"`
+	// Message describing the issue
+	Message string `json:"message" yaml:"message"`
+}
+
+// String formats the issue as file:line: message
+func (i Issue) String() string {
+	if i.Severity == SeverityWarning {
+		return fmt.Sprintf("%s:%d: warning: %s", i.File, i.Line, i.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", i.File, i.Line, i.Message)
+}
+
+// HasErrors reports whether any of the issues is an error
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteText writes the issues to w, one file:line: message per line
+func WriteText(w io.Writer, issues []Issue) error {
+	for _, issue := range issues {
+		if _, err := fmt.Fprintln(w, issue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LineAt returns the line of the offset within content, starting at 1
+func LineAt(content []byte, offset int) int {
+	return bytes.Count(content[:min(offset, len(content))], []byte("\n")) + 1
+}
+
+// CheckSections reports each of the required sections missing from the note
+func CheckSections(file string, note *markdown.NoteContent, required []string) []Issue {
+	var issues []Issue
+	for _, title := range required {
+		found := false
+		for _, section := range note.Sections {
+			if strings.EqualFold(section.Title, title) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			issues = append(issues, Issue{
+				File:     file,
+				Line:     1,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("missing required section %q", title),
+			})
+		}
+	}
+	return issues
+}
+
+// CheckFrontMatter reports malformed front matter, and a date within the
+// front matter differing from the date of the note
+func CheckFrontMatter(file string, content []byte, date time.Time) []Issue {
+	frontMatter, err := markdown.ParseFrontMatter(string(content))
+	if err != nil {
+		return []Issue{{File: file, Line: 1, Severity: SeverityError, Message: err.Error()}}
+	}
+
+	value, ok := frontMatter["date"]
+	if !ok {
+		return nil
+	}
+	lines, err := markdown.FrontMatterLines(string(content))
+	if err != nil {
+		return []Issue{{File: file, Line: 1, Severity: SeverityError, Message: err.Error()}}
+	}
+	line := lines["date"]
+
+	frontMatterDate, ok := markdown.ParseFrontMatterDate(value)
+	if !ok {
+		return []Issue{{File: file, Line: line, Severity: SeverityWarning, Message: fmt.Sprintf("unrecognised front matter date %v", value)}}
+	}
+
+	if frontMatterDate.Format("2006-01-02") != date.Format("2006-01-02") {
+		return []Issue{{
+			File:     file,
+			Line:     line,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("front matter date %s does not match the date of the note %s", frontMatterDate.Format("2006-01-02"), date.Format("2006-01-02")),
+		}}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCheckFrontMatter(t *testing.T) {
+	date := time.Date(2024, 12, 12, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		content string
+		want    []Issue
+	}{
+		{
+			name:    "matching date",
+			content: "---\ndate: 2024-12-12\n---\n# Daily\n",
+		},
+		{
+			name:    "no date",
+			content: "---\ntitle: daily\n---\n# Daily\ndate: 2024-12-11\n",
+		},
+		{
+			name:    "date after a key holding date",
+			content: "\n---\nupdate: weekly\ntitle: daily\ndate: 2024-12-11\n---\n# Daily\n",
+			want:    []Issue{{File: "2024-12-12.md", Line: 5, Severity: SeverityError, Message: "front matter date 2024-12-11 does not match the date of the note 2024-12-12"}},
+		},
+		{
+			name:    "unrecognised date",
+			content: "---\ntags: [journal]\ndate: soon\n---\n",
+			want:    []Issue{{File: "2024-12-12.md", Line: 3, Severity: SeverityWarning, Message: "unrecognised front matter date soon"}},
+		},
+		{
+			name:    "malformed",
+			content: "---\ndate: [2024\n---\n",
+			want:    []Issue{{File: "2024-12-12.md", Line: 1, Severity: SeverityError, Message: "invalid front matter: yaml: line 1: did not find expected ',' or ']'"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckFrontMatter("2024-12-12.md", []byte(tt.content), date); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckFrontMatter() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Package loader reads and parses many notes concurrently.
+package loader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/markdown"
+)
+
+// Source is a note to load
+type Source struct {
+	// Name of the note within the directory of its type
+	Name string
+	// Path of the note
+	Path string
+	// Date of the note
+	Date time.Time
+}
+
+// Note is a loaded note
+type Note struct {
+	Source
+	// Content is the parsed content of the note
+	Content *markdown.NoteContent
+}
+
+// LoadFunc reads and parses the note from source
+type LoadFunc func(ctx context.Context, source Source) (*markdown.NoteContent, error)
+
+// ParseFile returns a LoadFunc reading each note from disk and parsing it with
+// parser as a note of noteType
+func ParseFile(parser *markdown.Parser, skipText []string, noteType markdown.NoteType) LoadFunc {
+	return func(ctx context.Context, source Source) (*markdown.NoteContent, error) {
+		content, err := os.ReadFile(source.Path)
+		if err != nil {
+			return nil, err
+		}
+		return parser.ParseNoteContent(string(content), skipText, noteType, markdown.WithNoteName(source.Name))
+	}
+}
+
+// Loader loads notes with a bounded number of workers
+type Loader struct {
+	// Workers is the number of notes loaded at once (default GOMAXPROCS)
+	Workers int
+	// Load reads and parses each note
+	Load LoadFunc
+}
+
+// New returns a Loader loading notes with load on GOMAXPROCS workers
+func New(load LoadFunc) *Loader {
+	return &Loader{Workers: runtime.GOMAXPROCS(0), Load: load}
+}
+
+// LoadAll loads every one of sources, returning the notes ordered by date
+// then name. Loading stops at the first error, which is returned prefixed with
+// the name of the note, or when ctx is cancelled.
+func (l *Loader) LoadAll(ctx context.Context, sources []Source) ([]Note, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := l.Workers
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(sources))
+
+	notes := make([]Note, len(sources))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				content, err := l.Load(ctx, sources[i])
+				if err != nil {
+					once.Do(func() {
+						firstErr = fmt.Errorf("%s: %w", sources[i].Name, err)
+						cancel()
+					})
+					continue
+				}
+				notes[i] = Note{Source: sources[i], Content: content}
+			}
+		}()
+	}
+
+feed:
+	for i := range sources {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(notes, func(a, b Note) int {
+		if c := a.Date.Compare(b.Date); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return notes, nil
+}
@@ -0,0 +1,138 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/markdown"
+)
+
+// testSources returns n sources dated a day apart, newest first
+func testSources(n int) []Source {
+	start := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	sources := make([]Source, 0, n)
+	for i := n - 1; i >= 0; i-- {
+		dt := start.AddDate(0, 0, i)
+		name := dt.Format("2006-01-02") + ".md"
+		sources = append(sources, Source{Name: name, Path: "notes/" + name, Date: dt})
+	}
+	return sources
+}
+
+// loadBody is a LoadFunc returning the name of each note as its body
+// after a short random delay
+func loadBody(ctx context.Context, source Source) (*markdown.NoteContent, error) {
+	time.Sleep(time.Duration(rand.IntN(2000)) * time.Microsecond)
+	return &markdown.NoteContent{Body: source.Name}, nil
+}
+
+func TestLoadAllOrdersByDate(t *testing.T) {
+	sources := testSources(50)
+	rand.Shuffle(len(sources), func(i, j int) { sources[i], sources[j] = sources[j], sources[i] })
+
+	notes, err := (&Loader{Workers: 8, Load: loadBody}).LoadAll(context.Background(), sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != len(sources) {
+		t.Fatalf("got %d notes, want %d", len(notes), len(sources))
+	}
+	for i, note := range notes {
+		if note.Content.Body != note.Name {
+			t.Errorf("note %s has content of %s", note.Name, note.Content.Body)
+		}
+		if i > 0 && !notes[i-1].Date.Before(note.Date) {
+			t.Errorf("note %s ordered after %s", note.Name, notes[i-1].Name)
+		}
+	}
+}
+
+func TestLoadAllBoundsConcurrency(t *testing.T) {
+	for _, workers := range []int{1, 3, 8} {
+		t.Run(fmt.Sprint(workers), func(t *testing.T) {
+			var active, peak atomic.Int32
+			load := func(ctx context.Context, source Source) (*markdown.NoteContent, error) {
+				n := active.Add(1)
+				defer active.Add(-1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				return loadBody(ctx, source)
+			}
+
+			if _, err := (&Loader{Workers: workers, Load: load}).LoadAll(context.Background(), testSources(40)); err != nil {
+				t.Fatal(err)
+			}
+			if p := peak.Load(); p > int32(workers) {
+				t.Errorf("%d notes loaded at once, want at most %d", p, workers)
+			}
+		})
+	}
+}
+
+func TestLoadAllReturnsFirstError(t *testing.T) {
+	errBroken := errors.New("broken")
+	var loaded atomic.Int32
+	load := func(ctx context.Context, source Source) (*markdown.NoteContent, error) {
+		if source.Name == "2024-12-05.md" {
+			return nil, errBroken
+		}
+		loaded.Add(1)
+		return loadBody(ctx, source)
+	}
+
+	sources := testSources(500)
+	notes, err := (&Loader{Workers: 2, Load: load}).LoadAll(context.Background(), sources)
+	if !errors.Is(err, errBroken) {
+		t.Fatalf("got error %v, want %v", err, errBroken)
+	}
+	if !strings.HasPrefix(err.Error(), "2024-12-05.md: ") {
+		t.Errorf("error %q is not prefixed with the name of the note", err)
+	}
+	if notes != nil {
+		t.Errorf("got %d notes along with the error", len(notes))
+	}
+	// loading stops once a note fails
+	if n := loaded.Load(); n >= int32(len(sources)-1) {
+		t.Errorf("loaded %d notes after the error", n)
+	}
+}
+
+func TestLoadAllCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var loaded atomic.Int32
+	load := func(ctx context.Context, source Source) (*markdown.NoteContent, error) {
+		if loaded.Add(1) == 3 {
+			cancel()
+		}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	sources := testSources(100)
+	_, err := (&Loader{Workers: 4, Load: load}).LoadAll(ctx, sources)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	if n := loaded.Load(); n == int32(len(sources)) {
+		t.Errorf("every note was loaded despite cancellation")
+	}
+}
+
+func TestLoadAllEmpty(t *testing.T) {
+	notes, err := New(loadBody).LoadAll(context.Background(), nil)
+	if err != nil || len(notes) != 0 {
+		t.Errorf("LoadAll(nil) = %v, %v, want no notes", notes, err)
+	}
+}
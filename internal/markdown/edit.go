@@ -0,0 +1,85 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var listItemRegex = regexp.MustCompile(`^\s*(?:[*+-]|\d+[.)])\s`)
+
+// findSection returns the first section of the note with the given title,
+// ignoring case
+func findSection(note *NoteContent, title string) (Section, bool) {
+	for _, section := range note.Sections {
+		if strings.EqualFold(section.Title, title) {
+			return section, true
+		}
+	}
+	return Section{}, false
+}
+
+// AppendToSection returns content, the content note was parsed from, with
+// text appended after the existing content of the section with the given
+// title
+func AppendToSection(content []byte, note *NoteContent, title string, text string) ([]byte, error) {
+	section, ok := findSection(note, title)
+	if !ok {
+		return nil, fmt.Errorf("no %q section", title)
+	}
+
+	start := note.BodyOffset + section.ContentStart
+	end := note.BodyOffset + section.ContentEnd
+	existing := bytes.TrimRight(content[start:end], " \t\n")
+	at := start + len(existing)
+
+	text = strings.TrimRight(text, "\n")
+	var insert string
+	switch {
+	case len(existing) > 0:
+		insert = "\n" + text
+		// start a new block unless continuing a list
+		lastLine := existing[bytes.LastIndexByte(existing, '\n')+1:]
+		if !listItemRegex.Match(lastLine) {
+			insert = "\n" + insert
+		}
+		if at == len(content) {
+			insert += "\n"
+		}
+	case at > 0 && content[at-1] == '\n':
+		// separate the text from the heading with a blank line
+		insert = "\n" + text + "\n"
+	default:
+		insert = "\n\n" + text + "\n"
+	}
+	return slices.Concat(content[:at:at], []byte(insert), content[at:]), nil
+}
+
+// ReplaceLinkTargets returns content, the content note was parsed from, with
+// the target of each of the links replaced with the target of the same index
+func ReplaceLinkTargets(content []byte, note *NoteContent, links []AdjacentLink, targets []string) []byte {
+	order := make([]int, len(links))
+	for i := range order {
+		order[i] = i
+	}
+	// edit from the end of the note so earlier offsets remain valid
+	slices.SortFunc(order, func(a, b int) int {
+		return links[b].TargetStart - links[a].TargetStart
+	})
+
+	replaced := -1
+	for _, i := range order {
+		// links with several text nodes share their target
+		if links[i].TargetStart == replaced {
+			continue
+		}
+		replaced = links[i].TargetStart
+
+		start := note.BodyOffset + links[i].TargetStart
+		end := note.BodyOffset + links[i].TargetEnd
+		content = slices.Concat(content[:start:start], []byte(targets[i]), content[end:])
+	}
+	return content
+}
@@ -0,0 +1,67 @@
+package markdown
+
+import "testing"
+
+func TestAppendToSection(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "list",
+			content: "# Daily\n\n## Goals\n\n* [ ] a\n\n## Notes\n\n* b\n",
+			want:    "# Daily\n\n## Goals\n\n* [ ] a\n* [ ] new\n\n## Notes\n\n* b\n",
+		},
+		{
+			name:    "paragraph",
+			content: "## Goals\n\nSome text\n\n## Notes\n",
+			want:    "## Goals\n\nSome text\n\n* [ ] new\n\n## Notes\n",
+		},
+		{
+			name:    "empty section",
+			content: "## Goals\n\n## Notes\n",
+			want:    "## Goals\n\n* [ ] new\n\n## Notes\n",
+		},
+		{
+			name:    "last section",
+			content: "## Notes\n\n## Goals\n\n* [ ] a\n",
+			want:    "## Notes\n\n## Goals\n\n* [ ] a\n* [ ] new\n",
+		},
+		{
+			name:    "last section without a trailing newline",
+			content: "## Notes\n\n## Goals\n\n* [ ] a",
+			want:    "## Notes\n\n## Goals\n\n* [ ] a\n* [ ] new\n",
+		},
+		{
+			name:    "empty last section",
+			content: "## Notes\n\n## Goals",
+			want:    "## Notes\n\n## Goals\n\n* [ ] new\n",
+		},
+	}
+
+	parser := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note, err := parser.ParseNoteContent(tt.content, nil, NoteTypeJournal)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := AppendToSection([]byte(tt.content), note, "goals", "* [ ] new\n")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("AppendToSection() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	note, err := parser.ParseNoteContent("## Notes\n", nil, NoteTypeJournal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AppendToSection([]byte("## Notes\n"), note, "Goals", "* [ ] new"); err == nil {
+		t.Error("expected an error for a missing section")
+	}
+}
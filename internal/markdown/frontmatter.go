@@ -0,0 +1,89 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidFrontMatter is returned for front matter that is not valid YAML
+var ErrInvalidFrontMatter = errors.New("invalid front matter")
+
+// frontMatterDateLayouts are the layouts dates within front matter are parsed with
+var frontMatterDateLayouts = []string{
+	"2006-01-02",
+	"Monday, January 2, 2006",
+	"January 2, 2006",
+	"2006/01/02",
+	time.RFC3339,
+}
+
+// FrontMatter is the YAML front matter at the start of a note
+type FrontMatter struct {
+	// Title of the note (title)
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+	// Date of the note (date), zero when missing or not recognised
+	Date time.Time `json:"date,omitempty" yaml:"date,omitempty"`
+	// Tags of the note (tags), given as a list or a comma separated string
+	Tags []string `json:"tags" yaml:"tags"`
+	// Values holds every key of the front matter, including those above
+	Values map[string]interface{} `json:"values" yaml:"values"`
+}
+
+// newFrontMatter returns the front matter holding values
+func newFrontMatter(values map[string]interface{}) FrontMatter {
+	frontMatter := FrontMatter{Values: values}
+
+	if title, ok := values["title"].(string); ok {
+		frontMatter.Title = title
+	}
+	if date, ok := ParseFrontMatterDate(values["date"]); ok {
+		frontMatter.Date = date
+	}
+
+	switch tags := values["tags"].(type) {
+	case string:
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				frontMatter.Tags = append(frontMatter.Tags, tag)
+			}
+		}
+	case []interface{}:
+		for _, tag := range tags {
+			if tag != nil {
+				frontMatter.Tags = append(frontMatter.Tags, fmt.Sprint(tag))
+			}
+		}
+	}
+
+	return frontMatter
+}
+
+// HasTag reports whether the front matter is tagged with tag, ignoring case
+// and any leading #
+func (f FrontMatter) HasTag(tag string) bool {
+	tag = strings.TrimPrefix(tag, "#")
+	for _, t := range f.Tags {
+		if strings.EqualFold(strings.TrimPrefix(t, "#"), tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseFrontMatterDate returns the date held by a front matter value, either
+// a YAML timestamp or a string in one of the recognised layouts
+func ParseFrontMatterDate(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		for _, layout := range frontMatterDateLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
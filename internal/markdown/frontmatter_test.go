@@ -0,0 +1,66 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]interface{}
+		// body of the note once the front matter is removed
+		body string
+	}{
+		{
+			name:    "front matter",
+			content: "---\ntitle: daily\n---\n\n# Daily\n",
+			want:    map[string]interface{}{"title": "daily"},
+			body:    "# Daily",
+		},
+		{
+			name:    "byte order mark and blank lines",
+			content: "\ufeff\n\n---\r\ntitle: daily\r\n---\r\n# Daily\r\n",
+			want:    map[string]interface{}{"title": "daily"},
+			body:    "# Daily",
+		},
+		{
+			name:    "empty front matter",
+			content: "---\n---\n# Daily",
+			want:    map[string]interface{}{},
+			body:    "# Daily",
+		},
+		{
+			name:    "thematic breaks",
+			content: "# Daily\n\n---\n\n* item\n\n---\n",
+			body:    "# Daily\n\n---\n\n* item\n\n---",
+		},
+		{
+			name:    "not at the start",
+			content: "intro\n---\ntitle: daily\n---\n",
+			body:    "intro\n---\ntitle: daily\n---",
+		},
+	}
+
+	parser := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFrontMatter(tt.content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFrontMatter() = %v, want %v", got, tt.want)
+			}
+
+			note, err := parser.ParseNoteContent(tt.content, nil, NoteTypeJournal)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if note.Body != tt.body {
+				t.Errorf("Body = %q, want %q", note.Body, tt.body)
+			}
+		})
+	}
+}
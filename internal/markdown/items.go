@@ -0,0 +1,90 @@
+package markdown
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+)
+
+// ListItem is an item of a list such as "* Looked into SSO", including task
+// list items
+type ListItem struct {
+	// Text of the first line of the item, without the list marker or checkbox
+	Text string `json:"text" yaml:"text"`
+	// Depth of list nesting of the item, starting at 1
+	Depth int `json:"depth" yaml:"depth"`
+	// Section is the title of the section holding the item
+	Section string `json:"section" yaml:"section"`
+	// Task is true for task list items ("* [ ] ...")
+	Task bool `json:"task" yaml:"task"`
+	// Checked is true for completed task list items ("[x]")
+	Checked bool `json:"checked" yaml:"checked"`
+	// Type of the note holding the item
+	Type NoteType `json:"type" yaml:"type"`
+	// Note is the name of the note holding the item, when known
+	Note string `json:"note" yaml:"note"`
+	// Date of the note holding the item, when known
+	Date time.Time `json:"date" yaml:"date"`
+	// Line number of the item within the content the note was parsed from,
+	// starting at 1
+	Line int `json:"line" yaml:"line"`
+	// Offset is the byte offset of the text of the item within the content
+	// the note was parsed from
+	Offset int `json:"offset" yaml:"offset"`
+}
+
+// parseItems extracts the list items from the body, with offsets relative to
+// the body
+func parseItems(root ast.Node, source []byte) []ListItem {
+	items := make([]ListItem, 0)
+	var section string
+
+	for n := root.FirstChild(); n != nil; n = n.NextSibling() {
+		if heading, ok := n.(*ast.Heading); ok {
+			section = headingTitle(heading, source)
+			continue
+		}
+
+		_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+			if !entering || child.Kind() != ast.KindListItem {
+				return ast.WalkContinue, nil
+			}
+
+			// the text is held by the first block of the item
+			block := child.FirstChild()
+			if block == nil {
+				return ast.WalkContinue, nil
+			}
+			lines := block.Lines()
+			if lines == nil || lines.Len() == 0 {
+				return ast.WalkContinue, nil
+			}
+
+			var text []string
+			for i := 0; i < lines.Len(); i++ {
+				segment := lines.At(i)
+				text = append(text, string(bytes.TrimSpace(segment.Value(source))))
+			}
+
+			item := ListItem{
+				Text:    strings.Join(text, " "),
+				Depth:   listDepth(block),
+				Section: section,
+				Offset:  lines.At(0).Start,
+			}
+			if checkbox, ok := block.FirstChild().(*extast.TaskCheckBox); ok {
+				item.Task = true
+				item.Checked = checkbox.IsChecked
+				item.Text = strings.TrimSpace(taskCheckboxRegex.ReplaceAllString(item.Text, ""))
+			}
+
+			items = append(items, item)
+			return ast.WalkContinue, nil
+		})
+	}
+
+	return items
+}
@@ -1,31 +1,108 @@
 package markdown
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"net/url"
+	"path"
+	"path/filepath"
 	"regexp"
 	"slices"
 	"strings"
 
+	"github.com/rdark/standupnotes/internal/util"
+
 	"github.com/mvdan/xurls"
 	"github.com/yuin/goldmark"
-	meta "github.com/yuin/goldmark-meta"
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/text"
+	"gopkg.in/yaml.v3"
 )
 
+// Parser parses notes. It is not modified once created by NewParser, and
+// ParseNoteContent creates a new goldmark parser context on each call, so a
+// Parser is safe for concurrent use.
 type Parser struct {
-	md goldmark.Markdown
+	md              goldmark.Markdown
+	renderer        Renderer
+	filenameFormats map[NoteType]*util.FilenameFormat
+	noteDirs        map[NoteType]string
+	// noteTypes are the registered note types in the order they were registered
+	noteTypes []NoteType
+}
+
+// ParserOption configures a Parser
+type ParserOption func(*Parser)
+
+// WithRenderer sets the Renderer used to render section content and notes.
+// The default renders CommonMark.
+func WithRenderer(r Renderer) ParserOption {
+	return func(p *Parser) {
+		p.renderer = r
+	}
+}
+
+// WithFilenameFormat registers the note type along with the format notes of
+// the type are named with, used to recognise links to them. The default is
+// util.DefaultFilenameFormat.
+func WithFilenameFormat(noteType NoteType, format *util.FilenameFormat) ParserOption {
+	return func(p *Parser) {
+		p.registerNoteType(noteType)
+		p.filenameFormats[noteType] = format
+	}
+}
+
+// WithNoteDir registers the note type along with the directory holding notes
+// of the type, used to recognise links to them. Without it, note types are
+// assumed to be held in sibling directories named after the note type.
+func WithNoteDir(noteType NoteType, dir string) ParserOption {
+	return func(p *Parser) {
+		p.registerNoteType(noteType)
+		p.noteDirs[noteType] = filepath.Clean(dir)
+	}
+}
+
+// registerNoteType adds noteType to the note types links are classified as
+func (p *Parser) registerNoteType(noteType NoteType) {
+	if !slices.Contains(p.noteTypes, noteType) {
+		p.noteTypes = append(p.noteTypes, noteType)
+	}
+}
+
+// ParseOption configures the parsing of a single note
+type ParseOption func(*parseConfig)
+
+type parseConfig struct {
+	name      string
+	deepLists bool
+}
+
+// WithNoteName sets the name of the note being parsed, relative to the
+// directory of its note type, used to resolve relative links within it
+func WithNoteName(name string) ParseOption {
+	return func(c *parseConfig) {
+		c.name = name
+	}
+}
+
+// WithDeepLists parses notes with lists nested deeper than maxListDepth
+// rather than returning a ListDepthError, so the rest of the note can still
+// be checked
+func WithDeepLists() ParseOption {
+	return func(c *parseConfig) {
+		c.deepLists = true
+	}
 }
 
 // NewParser creates a new Markdown Parser.
-func NewParser() *Parser {
-	return &Parser{
+func NewParser(opts ...ParserOption) *Parser {
+	p := &Parser{
 		md: goldmark.New(
 			goldmark.WithExtensions(
-				meta.Meta,
+				extension.TaskList,
 				extension.NewLinkify(
 					extension.WithLinkifyAllowedProtocols([][]byte{
 						[]byte("http:"),
@@ -37,17 +114,35 @@ func NewParser() *Parser {
 				),
 			),
 		),
+		renderer:        newDialectRenderer(commonMarkDialect),
+		filenameFormats: make(map[NoteType]*util.FilenameFormat),
+		noteDirs:        make(map[NoteType]string),
+	}
+	for _, opt := range opts {
+		opt(p)
 	}
+	return p
 }
 
-func (p *Parser) ParseNoteContent(content string, skipText []string, noteType NoteType) (*NoteContent, error) {
+func (p *Parser) ParseNoteContent(content string, skipText []string, noteType NoteType, opts ...ParseOption) (*NoteContent, error) {
+	var config parseConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	values, err := ParseFrontMatter(content)
+	if err != nil {
+		return nil, err
+	}
+
 	bytes := []byte(content)
 
-	bodyStart := findBodyStartAfterFrontMatter(bytes)
+	_, _, bodyStart, _ := findFrontMatter(bytes)
 
 	body := strings.TrimSpace(
 		string(bytes[bodyStart:]),
 	)
+	bodyOffset := bodyStart + strings.Index(string(bytes[bodyStart:]), body)
 
 	bytes = []byte(body)
 
@@ -57,40 +152,187 @@ func (p *Parser) ParseNoteContent(content string, skipText []string, noteType No
 		parser.WithContext(context),
 	)
 
-	sections, err := parseSections(root, bytes, skipText)
+	adjacentLinks, err := p.parseAdjacentLinks(root, bytes, noteType, path.Dir(config.name))
 	if err != nil {
 		return nil, err
 	}
 
-	adjacentLinks, err := parseAdjacentLinks(root, bytes, noteType)
+	externalLinks := parseExternalLinks(root, bytes)
+
+	items := parseItems(root, bytes)
+	noteDate, hasDate := p.filenameFormat(noteType).Parse(config.name)
+	for i := range items {
+		items[i].Offset += bodyOffset
+		items[i].Line = strings.Count(content[:items[i].Offset], "\n") + 1
+		items[i].Type = noteType
+		items[i].Note = config.name
+		if hasDate {
+			items[i].Date = noteDate
+		}
+	}
+	tasks := tasksOf(items)
+
+	// an invalid marker only stops regions being rewritten, so is recorded
+	// rather than failing every command reading the note
+	regions, markerErr := parseRegions(root, bytes)
+	if markerErr != nil {
+		markerErr.Offset += bodyOffset
+	}
+
+	pruneSkipText(root, bytes, skipText)
+
+	sections, err := parseSections(root, bytes, p.renderer, config.deepLists)
 	if err != nil {
+		var depthErr *ListDepthError
+		if errors.As(err, &depthErr) {
+			depthErr.Offset += bodyOffset
+		}
 		return nil, err
 	}
 
 	return &NoteContent{
 		Body:          body,
+		BodyOffset:    bodyOffset,
 		Sections:      sections,
 		AdjacentLinks: adjacentLinks,
+		ExternalLinks: externalLinks,
+		Tasks:         tasks,
+		Items:         items,
+		Regions:       regions,
+		MarkerError:   markerErr,
+		FrontMatter:   newFrontMatter(values),
 	}, nil
 }
 
-var frontmatterRegex = regexp.MustCompile(`(?ms)^\s*-+\s*$.*?^\s*-+\s*$`)
-var fileDateRegex = regexp.MustCompile(`^\d{4}-(?:0[1-9]|1[0-2])-(?:0[1-9]|[12]\d|3[01])(?:\.md)?$`)
+// frontMatterRegex matches YAML front matter between --- lines at the start
+// of a note, after an optional byte order mark and blank lines
+var frontMatterRegex = regexp.MustCompile(`(?ms)\A\x{FEFF}?\s*^-{3,}[ \t]*\r?\n(.*?)^-{3,}[ \t]*\r?$`)
+
+// defaultNoteTypes are the note types links are classified as when none are
+// registered
+var defaultNoteTypes = []NoteType{NoteTypeJournal, NoteTypeStandup}
+
+// linkNoteTypes returns the note types a link from a note of sourceNoteType
+// may point to, most likely first
+func (p *Parser) linkNoteTypes(sourceNoteType NoteType) []NoteType {
+	noteTypes := p.noteTypes
+	if len(noteTypes) == 0 {
+		noteTypes = defaultNoteTypes
+	}
+	return append([]NoteType{sourceNoteType}, noteTypes...)
+}
 
-var relativeStandupRegex = regexp.MustCompile(`^\.\.\/standup\/\d{4}-(?:0[1-9]|1[0-2])-(?:0[1-9]|[12]\d|3[01])(?:\.md)?$`)
-var relativeJournalRegex = regexp.MustCompile(`^\.\.\/journal\/\d{4}-(?:0[1-9]|1[0-2])-(?:0[1-9]|[12]\d|3[01])(?:\.md)?$`)
+// filenameFormat returns the format notes of the given type are named with
+func (p *Parser) filenameFormat(noteType NoteType) *util.FilenameFormat {
+	if format, ok := p.filenameFormats[noteType]; ok {
+		return format
+	}
+	return util.DefaultFilenameFormat
+}
 
-func findBodyStartAfterFrontMatter(source []byte) int {
-	index := frontmatterRegex.FindIndex(source)
+// classifyLink returns the type of the note the destination links to, and
+// whether it links to a note at all. The destination is resolved relative to
+// sourceDir, the directory of the source note within its note type directory,
+// and matched against the configured note directories when they are known.
+// Absolute destinations only link to notes within the configured directories.
+func (p *Parser) classifyLink(destination string, sourceNoteType NoteType, sourceDir string) (NoteType, bool) {
+	if dir, ok := p.noteDirs[sourceNoteType]; ok {
+		target := filepath.FromSlash(destination)
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(dir, filepath.FromSlash(sourceDir), target)
+		}
+		for _, targetNoteType := range p.linkNoteTypes(sourceNoteType) {
+			targetDir, ok := p.noteDirs[targetNoteType]
+			if !ok {
+				continue
+			}
+			name, err := filepath.Rel(targetDir, target)
+			if err != nil || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+				continue
+			}
+			if _, ok := p.filenameFormat(targetNoteType).Parse(filepath.ToSlash(name)); ok {
+				return targetNoteType, true
+			}
+		}
+		return "", false
+	}
+
+	resolved := path.Join(sourceDir, destination)
+
+	if _, ok := p.filenameFormat(sourceNoteType).Parse(resolved); ok {
+		return sourceNoteType, true
+	}
+
+	// links to the sibling directory of a note type, including oddly linked notes of the same type
+	for _, targetNoteType := range p.linkNoteTypes(sourceNoteType) {
+		name, ok := strings.CutPrefix(resolved, "../"+string(targetNoteType)+"/")
+		if !ok {
+			continue
+		}
+		if _, ok := p.filenameFormat(targetNoteType).Parse(name); ok {
+			return targetNoteType, true
+		}
+	}
+
+	return "", false
+}
+
+// findFrontMatter returns the offsets of the YAML of the front matter at the
+// start of source and the offset its body starts at, or false if it has no
+// front matter
+func findFrontMatter(source []byte) (start int, end int, bodyStart int, ok bool) {
+	index := frontMatterRegex.FindSubmatchIndex(source)
 	if index == nil {
-		return 0
+		return 0, 0, 0, false
+	}
+	return index[2], index[3], index[1], true
+}
+
+// ParseFrontMatter returns the YAML front matter at the start of the content,
+// or nil if the content has no front matter
+func ParseFrontMatter(content string) (map[string]interface{}, error) {
+	start, end, _, ok := findFrontMatter([]byte(content))
+	if !ok {
+		return nil, nil
+	}
+
+	frontMatter := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(content[start:end]), &frontMatter); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFrontMatter, err)
+	}
+
+	return frontMatter, nil
+}
+
+// FrontMatterLines returns the line of each top level key of the YAML front
+// matter at the start of the content, starting at 1, or nil if the content
+// has no front matter
+func FrontMatterLines(content string) (map[string]int, error) {
+	start, end, _, ok := findFrontMatter([]byte(content))
+	if !ok {
+		return nil, nil
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(content[start:end]), &node); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFrontMatter, err)
 	}
 
-	return index[1]
+	lines := make(map[string]int)
+	if len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+		return lines, nil
+	}
+	// the lines of nodes start at 1 from the start of the YAML
+	offset := strings.Count(content[:start], "\n")
+	mapping := node.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		lines[mapping.Content[i].Value] = offset + mapping.Content[i].Line
+	}
+	return lines, nil
 }
 
-// parse
-func parseAdjacentLinks(root ast.Node, source []byte, sourceNoteType NoteType) ([]AdjacentLink, error) {
+// parseAdjacentLinks extracts the links to other notes
+func (p *Parser) parseAdjacentLinks(root ast.Node, source []byte, sourceNoteType NoteType, sourceDir string) ([]AdjacentLink, error) {
 	adjacentLinks := make([]AdjacentLink, 0)
 
 	err := ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
@@ -100,41 +342,29 @@ func parseAdjacentLinks(root ast.Node, source []byte, sourceNoteType NoteType) (
 			case ast.KindLink:
 				link := n.(*ast.Link)
 				if !isURL(string(link.Destination)) {
-					var targetNoteType NoteType
-
 					// work out the target note type and skip if it isn't one in scope for an AdjacentLink
-					// TODO: make this less ugly
-					if fileDateRegex.Match(link.Destination) {
-						targetNoteType = sourceNoteType
-						// match oddly linked of same type
-					} else if sourceNoteType == NoteTypeJournal && relativeJournalRegex.Match(link.Destination) {
-						targetNoteType = sourceNoteType
-					} else if sourceNoteType == NoteTypeJournal && relativeStandupRegex.Match(link.Destination) {
-						targetNoteType = NoteTypeStandup
-						// match oddly linked of same type
-					} else if sourceNoteType == NoteTypeStandup && relativeStandupRegex.Match(link.Destination) {
-						targetNoteType = sourceNoteType
-					} else if sourceNoteType == NoteTypeStandup && relativeJournalRegex.Match(link.Destination) {
-						targetNoteType = NoteTypeJournal
-					} else {
+					targetNoteType, ok := p.classifyLink(string(link.Destination), sourceNoteType, sourceDir)
+					if !ok {
+						return ast.WalkContinue, nil
+					}
+
+					offsets, ok := findLinkOffsets(link, source)
+					if !ok {
 						return ast.WalkContinue, nil
 					}
 
 					for child := link.FirstChild(); child != nil; child = child.NextSibling() {
 
-						if text := child.(*ast.Text); text != nil {
+						if text, ok := child.(*ast.Text); ok {
 							adjacentLink := AdjacentLink{
-								SourceNoteType:   sourceNoteType,
-								TargetNoteType:   targetNoteType,
-								Title:            string(text.Segment.Value(source)),
-								Target:           string(link.Destination),
-							}
-
-							if lines := link.Parent().Lines(); lines != nil && lines.Len() > 0 {
-								adjacentLink.LinkStart = lines.At(0).Start
-								adjacentLink.LinkEnd = lines.At(0).Stop
-							} else {
-								return ast.WalkContinue, nil
+								SourceNoteType: sourceNoteType,
+								TargetNoteType: targetNoteType,
+								Title:          string(text.Segment.Value(source)),
+								Target:         string(link.Destination),
+								LinkStart:      offsets.linkStart,
+								LinkEnd:        offsets.linkEnd,
+								TargetStart:    offsets.targetStart,
+								TargetEnd:      offsets.targetEnd,
 							}
 
 							adjacentLinks = append(adjacentLinks, adjacentLink)
@@ -154,181 +384,279 @@ func parseAdjacentLinks(root ast.Node, source []byte, sourceNoteType NoteType) (
 	return adjacentLinks, nil
 }
 
-// parseSections extracts each section from the body delimited by a heading
-func parseSections(root ast.Node, source []byte, skipText []string) ([]Section, error) {
-	const maxListDepth = 3
-	sections := make([]Section, 0)
-	var currentSection *Section
-	listLevel := 0
-	skipNext := false
+// ExternalLink is a link to a URL outside of the notes
+type ExternalLink struct {
+	// Title of the link, the URL itself for bare URLs
+	Title string `json:"title" yaml:"title"`
+	// URL the link points to
+	URL string `json:"url" yaml:"url"`
+	// Start byte offset of the link within the body
+	Start int `json:"start" yaml:"start"`
+	// End byte offset of the link within the body
+	End int `json:"end" yaml:"end"`
+}
 
-	err := ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
-		if skipNext {
-			skipNext = false
+// parseExternalLinks extracts the links to URLs, both inline links and bare
+// URLs
+func parseExternalLinks(root ast.Node, source []byte) []ExternalLink {
+	externalLinks := make([]ExternalLink, 0)
+
+	// the end of the last text seen, bare URLs are found after it
+	lastStop := 0
+	_ = ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := n.(type) {
+		case *ast.Text:
+			lastStop = n.Segment.Stop
+		case *ast.Link:
+			if !isURL(string(n.Destination)) {
+				return ast.WalkContinue, nil
+			}
+			externalLink := ExternalLink{
+				Title: string(n.Text(source)),
+				URL:   string(n.Destination),
+			}
+			if offsets, ok := findLinkOffsets(n, source); ok {
+				externalLink.Start, externalLink.End = offsets.linkStart, offsets.linkEnd
+				lastStop = offsets.linkEnd
+			}
+			externalLinks = append(externalLinks, externalLink)
 			return ast.WalkSkipChildren, nil
+		case *ast.AutoLink:
+			label := n.Label(source)
+			externalLink := ExternalLink{
+				Title: string(label),
+				URL:   string(n.URL(source)),
+			}
+			if i := bytes.Index(source[lastStop:], label); i >= 0 {
+				externalLink.Start = lastStop + i
+				externalLink.End = externalLink.Start + len(label)
+				lastStop = externalLink.End
+			}
+			externalLinks = append(externalLinks, externalLink)
 		}
+		return ast.WalkContinue, nil
+	})
 
-		if entering {
-			switch n.Kind() {
-			case ast.KindHeading:
-				// Start a new section when we hit a heading
-				if currentSection != nil {
-					if lines := n.Lines(); lines != nil && lines.Len() > 0 {
-						currentSection.ContentEnd = lines.At(0).Start
-					}
-					sections = append(sections, *currentSection)
-				}
+	return externalLinks
+}
 
-				// Extract heading text
-				heading := n.(*ast.Heading)
-				var title strings.Builder
-				for child := heading.FirstChild(); child != nil; child = child.NextSibling() {
-					// TODO: Link handling within headings?
-					if child.Kind() == ast.KindText {
-						if text := child.(*ast.Text); text != nil {
-							title.Write(text.Segment.Value(source))
-						}
-					}
-				}
+// linkOffsets are the byte offsets of an inline link within the source
+type linkOffsets struct {
+	linkStart, linkEnd     int
+	targetStart, targetEnd int
+}
 
-				// Create new section starting after the heading
-				if lines := n.Lines(); lines != nil && lines.Len() > 0 {
-					currentSection = &Section{
-						Title:        title.String(),
-						ContentStart: lines.At(lines.Len()-1).Stop + 1, // Start after the heading's newline
-					}
-				}
+// findLinkOffsets returns the offsets of an inline link such as
+// [title](target) within the source, and whether they could be found
+func findLinkOffsets(link *ast.Link, source []byte) (linkOffsets, bool) {
+	textStart, textStop := -1, -1
+	_ = ast.Walk(link, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if text, ok := n.(*ast.Text); ok && entering {
+			if textStart < 0 {
+				textStart = text.Segment.Start
+			}
+			textStop = text.Segment.Stop
+		}
+		return ast.WalkContinue, nil
+	})
+	if textStart < 1 || source[textStart-1] != '[' {
+		return linkOffsets{}, false
+	}
 
-			case ast.KindFencedCodeBlock:
-				if currentSection != nil {
-					code := n.(*ast.FencedCodeBlock)
-					if len(currentSection.Content) > 0 {
-						currentSection.Content += "\n"
-					}
-					currentSection.Content += "```"
-					if code.Info != nil {
-						currentSection.Content += string(code.Info.Segment.Value(source))
-					}
-					currentSection.Content += "\n"
-				}
+	// the target follows the closing "](" of the text
+	open := bytes.Index(source[textStop:], []byte("]("))
+	if open < 0 {
+		return linkOffsets{}, false
+	}
+	targetStart := textStop + open + 2
+	target := bytes.Index(source[targetStart:], link.Destination)
+	if target < 0 {
+		return linkOffsets{}, false
+	}
+	targetStart += target
+	targetEnd := targetStart + len(link.Destination)
 
-			case ast.KindCodeBlock:
-				if currentSection != nil {
-					if len(currentSection.Content) > 0 {
-						currentSection.Content += "\n"
-					}
-					currentSection.Content += "```\n"
-				}
+	closing := bytes.IndexByte(source[targetEnd:], ')')
+	if closing < 0 {
+		return linkOffsets{}, false
+	}
 
-			case ast.KindBlockquote:
-				if currentSection != nil {
-					if len(currentSection.Content) > 0 {
-						currentSection.Content += "\n"
-					}
-					currentSection.Content += "> "
-				}
+	return linkOffsets{
+		linkStart:   textStart - 1,
+		linkEnd:     targetEnd + closing + 1,
+		targetStart: targetStart,
+		targetEnd:   targetEnd,
+	}, true
+}
 
-			case ast.KindText:
-				if currentSection != nil {
-					text := n.(*ast.Text)
-					// no headings or paragraphs
-					if !isParentKind(n, ast.KindHeading) && !isParentKind(n, ast.KindParagraph) {
-						content := text.Segment.Value(source)
-						if !slices.Contains(skipText, string(content)) && len(content) > 0 {
-							appendToSnippet(currentSection, string(content), false)
-						}
-					}
-				}
+// maxListDepth is the deepest level of list nesting allowed within a section
+const maxListDepth = 3
 
-			case ast.KindLink:
-				if currentSection != nil && !isParentKind(n, ast.KindHeading) {
-					link := n.(*ast.Link)
-					if isURL(string(link.Destination)) {
-						appendToSnippet(currentSection, "[", false)
-						for child := link.FirstChild(); child != nil; child = child.NextSibling() {
-							if text := child.(*ast.Text); text != nil {
-								appendToSnippet(currentSection, string(text.Segment.Value(source)), false)
-							}
-						}
-						appendToSnippet(currentSection, "]("+strings.TrimSpace(string(link.Destination))+")", false)
-						skipNext = true
-					}
-				}
+// parseSections extracts each section from the body delimited by a heading,
+// rendering the content of each with r. Paragraphs outside of lists are not
+// included in the content.
+func parseSections(root ast.Node, source []byte, r Renderer, deepLists bool) ([]Section, error) {
+	sections := make([]Section, 0)
+	var currentSection *Section
+	var content strings.Builder
 
-			case ast.KindList:
-				listLevel++
-				if listLevel > maxListDepth {
-					return ast.WalkStop, fmt.Errorf("list nesting too deep: maximum allowed is %d levels", maxListDepth)
-				}
-				return ast.WalkContinue, nil
+	closeSection := func(end int) {
+		if currentSection == nil {
+			return
+		}
+		currentSection.ContentEnd = end
+		if rendered := strings.TrimRight(content.String(), "\n"); rendered != "" {
+			currentSection.Content = rendered + "\n"
+		}
+		sections = append(sections, *currentSection)
+		content.Reset()
+	}
 
-			case ast.KindListItem:
-				if currentSection != nil {
-					if len(currentSection.Content) > 0 {
-						currentSection.Content += "\n"
-					}
-					level := listLevel
-					if level > maxListDepth {
-						level = maxListDepth
-					}
-					currentSection.Content += strings.Repeat("    ", level-1) + "* "
-				}
+	for n := root.FirstChild(); n != nil; n = n.NextSibling() {
+		if n.Kind() == ast.KindHeading {
+			// Start a new section when we hit a heading
+			lines := n.Lines()
+			if lines == nil || lines.Len() == 0 {
+				continue
 			}
-		} else { // exiting node
-			switch n.Kind() {
-			case ast.KindList:
-				listLevel--
-				if currentSection != nil && listLevel == 0 {
-					currentSection.Content += "\n"
-				}
-			case ast.KindFencedCodeBlock, ast.KindCodeBlock:
-				if currentSection != nil {
-					currentSection.Content += "\n```\n"
-				}
-			case ast.KindBlockquote:
-				if currentSection != nil {
-					currentSection.Content += "\n"
-				}
+			closeSection(lineStart(source, lines.At(0).Start))
+
+			// Create new section starting after the heading
+			heading := n.(*ast.Heading)
+			currentSection = &Section{
+				Title:        headingTitle(heading, source),
+				ContentStart: nextLineStart(source, lines.At(lines.Len()-1).Stop), // Start after the heading's newline
 			}
+			continue
+		}
+
+		if currentSection == nil || n.Kind() == ast.KindParagraph {
+			continue
+		}
+
+		if list := findTooDeepList(n); list != nil && !deepLists {
+			return nil, &ListDepthError{Depth: listDepth(list), Offset: firstLineStart(list)}
+		}
+
+		if err := r.Render(&content, source, n); err != nil {
+			return nil, err
+		}
+	}
+
+	// Add the last section if exists
+	closeSection(len(source))
+
+	return sections, nil
+}
+
+// ListDepthError is returned for notes with lists nested deeper than
+// maxListDepth
+type ListDepthError struct {
+	// Depth of the list
+	Depth int
+	// Offset is the byte offset of the list within the content the note was
+	// parsed from
+	Offset int
+}
+
+func (e *ListDepthError) Error() string {
+	return fmt.Sprintf("list nesting too deep: maximum allowed is %d levels", maxListDepth)
+}
+
+// findTooDeepList returns the first list within n nested deeper than
+// maxListDepth, or nil
+func findTooDeepList(n ast.Node) ast.Node {
+	var found ast.Node
+	_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && child.Kind() == ast.KindList && listDepth(child) > maxListDepth {
+			found = child
+			return ast.WalkStop, nil
 		}
 		return ast.WalkContinue, nil
 	})
+	return found
+}
 
-	if err != nil {
-		return nil, err
+// firstLineStart returns the offset of the first line of source within n
+func firstLineStart(n ast.Node) int {
+	offset := -1
+	_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && child.Type() == ast.TypeBlock {
+			if lines := child.Lines(); lines != nil && lines.Len() > 0 {
+				offset = lines.At(0).Start
+				return ast.WalkStop, nil
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return max(offset, 0)
+}
+
+// lineStart returns the offset of the start of the line containing pos
+func lineStart(source []byte, pos int) int {
+	return bytes.LastIndexByte(source[:pos], '\n') + 1
+}
+
+// nextLineStart returns the offset of the start of the line following pos, or
+// the length of source if pos is on the last line
+func nextLineStart(source []byte, pos int) int {
+	if i := bytes.IndexByte(source[pos:], '\n'); i >= 0 {
+		return pos + i + 1
 	}
+	return len(source)
+}
 
-	// Add the last section if exists
-	if currentSection != nil {
-		if lines := root.Lines(); lines != nil && lines.Len() > 0 {
-			currentSection.ContentEnd = lines.At(lines.Len() - 1).Stop
+// headingTitle returns the text of the heading
+func headingTitle(heading *ast.Heading, source []byte) string {
+	var title strings.Builder
+	for child := heading.FirstChild(); child != nil; child = child.NextSibling() {
+		// TODO: Link handling within headings?
+		if child.Kind() == ast.KindText {
+			if text := child.(*ast.Text); text != nil {
+				title.Write(text.Segment.Value(source))
+			}
 		}
-		sections = append(sections, *currentSection)
 	}
+	return title.String()
+}
 
-	return sections, nil
+// DropCompletedTasks removes completed task items ("[x]") of bullet and
+// ordered lists, along with any items nested beneath them, from section
+// content. Ordered lists are not renumbered.
+func DropCompletedTasks(content string) string {
+	var kept []string
+	dropIndent := -1
+	for _, line := range strings.Split(content, "\n") {
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if dropIndent >= 0 {
+			if indent > dropIndent && strings.TrimSpace(line) != "" {
+				continue
+			}
+			dropIndent = -1
+		}
+		if completedTaskRegex.MatchString(line) {
+			dropIndent = indent
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
 }
 
+var completedTaskRegex = regexp.MustCompile(`^\s*(?:[*+-]|\d+[.)]) \[[xX]\]\s`)
+
+var taskCheckboxRegex = regexp.MustCompile(`^\[[\sxX]\]\s*`)
+
 // Helper function to check if a node's parent is of a specific kind
 func isParentKind(n ast.Node, kind ast.NodeKind) bool {
 	parent := n.Parent()
 	return parent != nil && parent.Kind() == kind
 }
 
-func appendToSnippet(section *Section, content string, addSpace bool) {
-	if section == nil {
-		return
-	}
-
-	if addSpace && len(section.Content) > 0 &&
-		!strings.HasSuffix(section.Content, "\n") &&
-		!strings.HasSuffix(section.Content, " ") &&
-		!strings.HasPrefix(content, "\n") {
-		section.Content += " "
-	}
-	section.Content += content
-}
-
 // isURL returns whether the given string is a valid URL.
 func isURL(s string) bool {
 	_, err := url.ParseRequestURI(s)
@@ -344,59 +672,76 @@ func isURL(s string) bool {
 	return true
 }
 
-type NoteType int
+// NoteType is the name of a kind of note, such as journal or retro. Any name
+// may be used; journal and standup are built in.
+type NoteType string
 
-// Note types
+// Built-in note types
 const (
-	NoteTypeJournal NoteType = iota
-	NoteTypeStandup
+	NoteTypeJournal NoteType = "journal"
+	NoteTypeStandup NoteType = "standup"
 )
 
 func (t NoteType) String() string {
-    switch t {
-    case NoteTypeJournal:
-        return "NoteTypeJournal"
-    case NoteTypeStandup:
-        return "NoteTypeStandup"
-    default:
-        return fmt.Sprintf("%d", int(t))
-    }
+	return string(t)
 }
 
 // Section represents a portion of the overall document delimited by a heading
 type Section struct {
 	// Label of the link.
-	Title string
+	Title string `json:"title" yaml:"title"`
 	// Content of the section
-	Content string
+	Content string `json:"content" yaml:"content"`
 	// Start byte offset of the content
-	ContentStart int
+	ContentStart int `json:"content_start" yaml:"content_start"`
 	// End byte offset of the content
-	ContentEnd int
+	ContentEnd int `json:"content_end" yaml:"content_end"`
 }
 
 // AdjacentLink represents a link to an adjacent note
 type AdjacentLink struct {
 	// Type of the note where the link is defined
-	SourceNoteType NoteType
+	SourceNoteType NoteType `json:"source_note_type" yaml:"source_note_type"`
 	// Type of the note where the link points to
-	TargetNoteType NoteType
+	TargetNoteType NoteType `json:"target_note_type" yaml:"target_note_type"`
 	// The title of the link, matched from config; used to determine the type
-	Title            string
+	Title string `json:"title" yaml:"title"`
 	// The target of the link
-	Target string
-	// Start byte offset of the link as defined in the body
-	LinkStart int
-	// End byte offset of the link as defined in the body
-	LinkEnd int
+	Target string `json:"target" yaml:"target"`
+	// Start byte offset of the link as defined in the body, at the opening "["
+	LinkStart int `json:"link_start" yaml:"link_start"`
+	// End byte offset of the link as defined in the body, after the closing ")"
+	LinkEnd int `json:"link_end" yaml:"link_end"`
+	// Start byte offset of the target within the body
+	TargetStart int `json:"target_start" yaml:"target_start"`
+	// End byte offset of the target within the body
+	TargetEnd int `json:"target_end" yaml:"target_end"`
 }
 
 // NoteContent holds the data parsed from the note content.
 type NoteContent struct {
 	// Body is the content of the note
-	Body string
+	Body string `json:"body" yaml:"body"`
+	// BodyOffset is the byte offset of Body within the content the note was
+	// parsed from; offsets within the note are relative to Body
+	BodyOffset int `json:"body_offset" yaml:"body_offset"`
 	// Sections is a list of the sections within the body
-	Sections []Section
+	Sections []Section `json:"sections" yaml:"sections"`
 	// A list of adjacent links
-	AdjacentLinks []AdjacentLink
+	AdjacentLinks []AdjacentLink `json:"adjacent_links" yaml:"adjacent_links"`
+	// ExternalLinks are the links to URLs, including bare URLs
+	ExternalLinks []ExternalLink `json:"external_links" yaml:"external_links"`
+	// Tasks are the task list items within the body, with offsets relative to
+	// the content the note was parsed from
+	Tasks []Task `json:"tasks" yaml:"tasks"`
+	// Items are the list items within the body, including task list items,
+	// with offsets relative to the content the note was parsed from
+	Items []ListItem `json:"items" yaml:"items"`
+	// Regions are the regions delimited by markers within the body
+	Regions []Region `json:"regions" yaml:"regions"`
+	// MarkerError is the first invalid marker of the note, with the regions
+	// before it in Regions, or nil when every marker delimits a region
+	MarkerError *MarkerError `json:"marker_error,omitempty" yaml:"marker_error,omitempty"`
+	// FrontMatter is the front matter of the note, empty when it has none
+	FrontMatter FrontMatter `json:"front_matter" yaml:"front_matter"`
 }
@@ -0,0 +1,132 @@
+package markdown
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/rdark/standupnotes/internal/util"
+)
+
+// testJournal is a journal exercising links, tasks, lists and front matter
+const testJournal = `---
+title: daily-2024-12-12
+tags: [journal, company:acme]
+---
+
+# Daily 2024-12-12
+
+[Yesterday](2024-12-11)
+[Tomorrow](2024-12-13)
+
+## Goals of the Day
+
+* [ ] Review [PLA-77](https://linear.app/acme/issue/PLA-77)
+* [x] Deploy <https://example.com>
+    * nested item
+
+## Worked On
+
+* Fixed SSO for acme/api#12
+* Looked into caching
+
+<!-- standup:begin today -->
+* synced
+<!-- standup:end today -->
+`
+
+func TestParseNoteContentConcurrent(t *testing.T) {
+	parser := NewParser(
+		WithFilenameFormat(NoteTypeJournal, util.DefaultFilenameFormat),
+		WithFilenameFormat(NoteTypeStandup, util.DefaultFilenameFormat),
+	)
+
+	want, err := parser.ParseNoteContent(testJournal, []string{"Looked into"}, NoteTypeJournal, WithNoteName("2024-12-12.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(want.Sections) != 3 || len(want.AdjacentLinks) != 2 || len(want.Tasks) != 2 || len(want.Regions) != 1 {
+		t.Fatalf("unexpected parse of the test journal: %+v", want)
+	}
+
+	// a single parser shared between goroutines gives the same results as
+	// parsing sequentially; run with -race to check for data races
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				got, err := parser.ParseNoteContent(testJournal, []string{"Looked into"}, NoteTypeJournal, WithNoteName("2024-12-12.md"))
+				if err != nil {
+					errs <- err
+					return
+				}
+				if !reflect.DeepEqual(got, want) {
+					errs <- fmt.Errorf("concurrent parse differs:\n%+v\nwant\n%+v", got, want)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestClassifyLink(t *testing.T) {
+	nested, err := util.ParseFilenameFormat("%Y/%m/%d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaultDirs := NewParser(
+		WithFilenameFormat(NoteTypeJournal, util.DefaultFilenameFormat),
+		WithFilenameFormat(NoteTypeStandup, util.DefaultFilenameFormat),
+	)
+	configuredDirs := NewParser(
+		WithNoteDir(NoteTypeJournal, "/notes/daily"),
+		WithNoteDir(NoteTypeStandup, "/notes/work/standup"),
+		WithFilenameFormat(NoteTypeStandup, nested),
+	)
+
+	tests := []struct {
+		name        string
+		parser      *Parser
+		destination string
+		noteType    NoteType
+		sourceDir   string
+		// type of the note linked to, empty when not a note
+		want NoteType
+	}{
+		{name: "same type", parser: defaultDirs, destination: "2024-12-11", noteType: NoteTypeJournal, want: NoteTypeJournal},
+		{name: "same type with extension", parser: defaultDirs, destination: "2024-12-11.md", noteType: NoteTypeJournal, want: NoteTypeJournal},
+		{name: "sibling directory", parser: defaultDirs, destination: "../standup/2024-12-11", noteType: NoteTypeJournal, want: NoteTypeStandup},
+		{name: "sibling directory with extension", parser: defaultDirs, destination: "../standup/2024-12-11.md", noteType: NoteTypeJournal, want: NoteTypeStandup},
+		{name: "not a date", parser: defaultDirs, destination: "ideas.md", noteType: NoteTypeJournal},
+		{name: "unknown directory", parser: defaultDirs, destination: "../archive/2024-12-11", noteType: NoteTypeJournal},
+		{name: "absolute", parser: defaultDirs, destination: "/2024-12-11", noteType: NoteTypeJournal},
+
+		{name: "configured same type", parser: configuredDirs, destination: "2024-12-11", noteType: NoteTypeJournal, want: NoteTypeJournal},
+		{name: "configured other type", parser: configuredDirs, destination: "../work/standup/2024/12/11", noteType: NoteTypeJournal, want: NoteTypeStandup},
+		{name: "configured sibling directory", parser: configuredDirs, destination: "../standup/2024-12-11", noteType: NoteTypeJournal},
+		{name: "configured outside directory", parser: configuredDirs, destination: "../daily-old/2024-12-11", noteType: NoteTypeJournal},
+		{name: "configured nested", parser: configuredDirs, destination: "11", noteType: NoteTypeStandup, sourceDir: "2024/12", want: NoteTypeStandup},
+		{name: "configured nested other type", parser: configuredDirs, destination: "../../../../daily/2024-12-11.md", noteType: NoteTypeStandup, sourceDir: "2024/12", want: NoteTypeJournal},
+		{name: "configured absolute", parser: configuredDirs, destination: "/notes/daily/2024-12-11.md", noteType: NoteTypeStandup, sourceDir: "2024/12", want: NoteTypeJournal},
+		{name: "configured absolute outside directory", parser: configuredDirs, destination: "/2024-12-11", noteType: NoteTypeJournal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.parser.classifyLink(tt.destination, tt.noteType, tt.sourceDir)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("classifyLink(%q) = %q, %v, want %q", tt.destination, got, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// Regions of notes managed by standupnotes are delimited by HTML comment
+// markers on lines of their own, e.g.
+//
+//	<!-- standup:begin today -->
+//	* [ ] Review SSO PR
+//	<!-- standup:end today -->
+//
+// Only the lines between the markers are rewritten, so everything else in
+// the note, including the markers themselves, is preserved byte for byte.
+var markerRegex = regexp.MustCompile(`^<!--\s*standup:(begin|end)\s+([\w.-]+)\s*-->$`)
+
+// Region is a part of a note managed by standupnotes, delimited by markers
+type Region struct {
+	// Name of the region, as given in its markers
+	Name string `json:"name" yaml:"name"`
+	// Section is the title of the section holding the region
+	Section string `json:"section" yaml:"section"`
+	// Content between the markers
+	Content string `json:"content" yaml:"content"`
+	// Start byte offset of the content, after the line of the begin marker
+	ContentStart int `json:"content_start" yaml:"content_start"`
+	// End byte offset of the content, at the start of the line of the end
+	// marker
+	ContentEnd int `json:"content_end" yaml:"content_end"`
+}
+
+// MarkerError describes a marker that does not delimit a region. Notes are
+// still parsed with one, recording it on the note, but their regions are not
+// rewritten.
+type MarkerError struct {
+	// Name of the region of the marker
+	Name string `json:"name" yaml:"name"`
+	// Reason the marker is invalid
+	Reason string `json:"reason" yaml:"reason"`
+	// Offset is the byte offset of the marker within the content the note was
+	// parsed from
+	Offset int `json:"offset" yaml:"offset"`
+}
+
+func (e *MarkerError) Error() string {
+	return fmt.Sprintf("%s marker for region %q", e.Reason, e.Name)
+}
+
+// parseRegions extracts the regions delimited by markers from the body, with
+// offsets relative to the body. Markers are only recognised as HTML blocks
+// outside of lists, quotes and code. Parsing stops at the first invalid
+// marker, returning it along with the regions before it.
+func parseRegions(root ast.Node, source []byte) ([]Region, *MarkerError) {
+	regions := make([]Region, 0)
+	var section string
+	var open *Region
+	var openAt int
+
+	for n := root.FirstChild(); n != nil; n = n.NextSibling() {
+		if heading, ok := n.(*ast.Heading); ok {
+			section = headingTitle(heading, source)
+			continue
+		}
+		block, ok := n.(*ast.HTMLBlock)
+		if !ok {
+			continue
+		}
+
+		lines := block.Lines()
+		segments := lines.Sliced(0, lines.Len())
+		if block.HasClosure() {
+			segments = append(segments, block.ClosureLine)
+		}
+		for _, segment := range segments {
+			match := markerRegex.FindSubmatch(bytes.TrimSpace(segment.Value(source)))
+			if match == nil {
+				continue
+			}
+			kind, name := string(match[1]), string(match[2])
+
+			switch {
+			case kind == "begin" && open != nil:
+				return regions, &MarkerError{Name: name, Reason: fmt.Sprintf("nested begin (within %q)", open.Name), Offset: segment.Start}
+			case kind == "begin" && slices.ContainsFunc(regions, func(r Region) bool { return r.Name == name }):
+				return regions, &MarkerError{Name: name, Reason: "duplicate begin", Offset: segment.Start}
+			case kind == "begin":
+				open = &Region{Name: name, Section: section, ContentStart: nextLineStart(source, segment.Start)}
+				openAt = segment.Start
+			case open == nil || open.Name != name:
+				return regions, &MarkerError{Name: name, Reason: "unmatched end", Offset: segment.Start}
+			default:
+				open.ContentEnd = lineStart(source, segment.Start)
+				open.Content = string(source[open.ContentStart:open.ContentEnd])
+				regions = append(regions, *open)
+				open = nil
+			}
+		}
+	}
+
+	if open != nil {
+		return regions, &MarkerError{Name: open.Name, Reason: "unmatched begin", Offset: openAt}
+	}
+	return regions, nil
+}
+
+// FindRegion returns the region of the note with the given name
+func FindRegion(note *NoteContent, name string) (Region, bool) {
+	for _, region := range note.Regions {
+		if region.Name == name {
+			return region, true
+		}
+	}
+	return Region{}, false
+}
+
+// ReplaceRegion returns content, the content note was parsed from, with the
+// lines between the markers of the named region replaced with text. Nothing
+// outside of the markers is changed. The MarkerError of the note is returned
+// when it has an invalid marker.
+func ReplaceRegion(content []byte, note *NoteContent, name string, text string) ([]byte, error) {
+	if note.MarkerError != nil {
+		return nil, note.MarkerError
+	}
+	region, ok := FindRegion(note, name)
+	if !ok {
+		return nil, fmt.Errorf("no %q region", name)
+	}
+
+	start := note.BodyOffset + region.ContentStart
+	end := note.BodyOffset + region.ContentEnd
+
+	var replacement string
+	if text = strings.Trim(text, "\n"); text != "" {
+		replacement = text + "\n"
+	}
+	return slices.Concat(content[:start:start], []byte(replacement), content[end:]), nil
+}
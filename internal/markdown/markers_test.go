@@ -0,0 +1,151 @@
+package markdown
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReplaceRegion(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		text    string
+		want    string
+	}{
+		{
+			name:    "replace",
+			content: "# Standup\n\n## Today\n\n<!-- standup:begin today -->\n* old\n<!-- standup:end today -->\n\n## Blockers\n\n* none\n",
+			text:    "* new\n* newer",
+			want:    "# Standup\n\n## Today\n\n<!-- standup:begin today -->\n* new\n* newer\n<!-- standup:end today -->\n\n## Blockers\n\n* none\n",
+		},
+		{
+			name:    "append",
+			content: "<!-- standup:begin today -->\n* old\n<!-- standup:end today -->\n",
+			text:    "* old\n* new\n",
+			want:    "<!-- standup:begin today -->\n* old\n* new\n<!-- standup:end today -->\n",
+		},
+		{
+			name:    "empty region",
+			content: "---\ntitle: standup\n---\n\n<!-- standup:begin today -->\n<!-- standup:end today -->",
+			text:    "* new",
+			want:    "---\ntitle: standup\n---\n\n<!-- standup:begin today -->\n* new\n<!-- standup:end today -->",
+		},
+		{
+			name:    "emptied",
+			content: "<!--standup:begin today-->\n* old\n<!--standup:end today-->\n",
+			want:    "<!--standup:begin today-->\n<!--standup:end today-->\n",
+		},
+		{
+			name:    "outside left as is",
+			content: "# Standup  \r\n\n*  odd   spacing*\n\n<!-- standup:begin work-done -->\n* done\n<!-- standup:end work-done -->\n<!-- standup:begin today -->\n* old\n<!-- standup:end today -->\n\n\n[Yesterday]( 2024-12-11 )\n",
+			text:    "* new",
+			want:    "# Standup  \r\n\n*  odd   spacing*\n\n<!-- standup:begin work-done -->\n* done\n<!-- standup:end work-done -->\n<!-- standup:begin today -->\n* new\n<!-- standup:end today -->\n\n\n[Yesterday]( 2024-12-11 )\n",
+		},
+	}
+
+	parser := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note, err := parser.ParseNoteContent(tt.content, nil, NoteTypeStandup)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReplaceRegion([]byte(tt.content), note, "today", tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ReplaceRegion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRegions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		// names of the regions found
+		regions []string
+		// reason of the marker error, and the text the invalid marker starts at
+		reason string
+		marker string
+	}{
+		{
+			name:    "regions",
+			content: "## Done\n\n<!-- standup:begin work-done -->\n* a\n<!-- standup:end work-done -->\n\n## Today\n\n<!-- standup:begin today -->\n<!-- standup:end today -->\n",
+			regions: []string{"work-done", "today"},
+		},
+		{
+			name:    "missing end",
+			content: "<!-- standup:begin work-done -->\n* a\n<!-- standup:end work-done -->\n\n<!-- standup:begin today -->\n* b\n",
+			regions: []string{"work-done"},
+			reason:  "unmatched begin",
+			marker:  "<!-- standup:begin today",
+		},
+		{
+			name:    "missing begin",
+			content: "* a\n\n<!-- standup:end today -->\n",
+			reason:  "unmatched end",
+			marker:  "<!-- standup:end today",
+		},
+		{
+			name:    "duplicate",
+			content: "<!-- standup:begin today -->\n* a\n<!-- standup:end today -->\n\n<!-- standup:begin today -->\n<!-- standup:end today -->\n",
+			regions: []string{"today"},
+			reason:  "duplicate begin",
+			marker:  "<!-- standup:begin today -->\n<!-- standup:end",
+		},
+		{
+			name:    "nested",
+			content: "<!-- standup:begin today -->\n<!-- standup:begin work-done -->\n<!-- standup:end work-done -->\n<!-- standup:end today -->\n",
+			reason:  `nested begin (within "today")`,
+			marker:  "<!-- standup:begin work-done",
+		},
+		{
+			name:    "code block",
+			content: "```\n<!-- standup:begin today -->\n```\n\n    <!-- standup:end today -->\n\n* <!-- standup:begin today -->\n",
+		},
+	}
+
+	parser := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note, err := parser.ParseNoteContent(tt.content, nil, NoteTypeStandup)
+			if err != nil {
+				t.Fatalf("an invalid marker failed the parse: %v", err)
+			}
+
+			names := make([]string, 0)
+			for _, region := range note.Regions {
+				names = append(names, region.Name)
+			}
+			if !reflect.DeepEqual(names, append(make([]string, 0), tt.regions...)) {
+				t.Errorf("got regions %v, want %v", names, tt.regions)
+			}
+
+			if tt.reason == "" {
+				if note.MarkerError != nil {
+					t.Errorf("unexpected marker error %v", note.MarkerError)
+				}
+				return
+			}
+			if note.MarkerError == nil {
+				t.Fatalf("got no marker error, want %s", tt.reason)
+			}
+			if note.MarkerError.Reason != tt.reason {
+				t.Errorf("got reason %q, want %q", note.MarkerError.Reason, tt.reason)
+			}
+			if at := tt.content[note.MarkerError.Offset:]; !strings.HasPrefix(at, tt.marker) {
+				t.Errorf("marker error at %q, want %q", at, tt.marker)
+			}
+
+			_, err = ReplaceRegion([]byte(tt.content), note, "today", "* new")
+			if !errors.Is(err, note.MarkerError) {
+				t.Errorf("ReplaceRegion() got error %v, want the marker error", err)
+			}
+		})
+	}
+}
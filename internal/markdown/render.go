@@ -0,0 +1,645 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	gutil "github.com/yuin/goldmark/util"
+)
+
+// Renderer writes markdown AST nodes in a particular output dialect
+type Renderer interface {
+	// Render writes the node n, read from source, to w
+	Render(w io.Writer, source []byte, n ast.Node) error
+	// Heading returns a heading of the given level, without a trailing newline
+	Heading(level int, title string) string
+}
+
+// Output formats supported by NewRenderer
+const (
+	FormatCommonMark = "markdown"
+	FormatSlack      = "slack"
+	FormatTeams      = "teams"
+	FormatDiscord    = "discord"
+	FormatPlain      = "plain"
+	FormatHTML       = "html"
+)
+
+// Formats lists the output formats supported by NewRenderer
+var Formats = []string{FormatCommonMark, FormatSlack, FormatTeams, FormatDiscord, FormatPlain, FormatHTML}
+
+// NewRenderer returns the Renderer for the named output format
+func NewRenderer(format string) (Renderer, error) {
+	switch format {
+	case FormatCommonMark:
+		return newDialectRenderer(commonMarkDialect), nil
+	case FormatSlack:
+		return newDialectRenderer(slackDialect), nil
+	case FormatTeams:
+		return newDialectRenderer(teamsDialect), nil
+	case FormatDiscord:
+		return newDialectRenderer(discordDialect), nil
+	case FormatPlain:
+		return newDialectRenderer(plainDialect), nil
+	case FormatHTML:
+		return &htmlRenderer{
+			r: renderer.NewRenderer(
+				renderer.WithNodeRenderers(
+					gutil.Prioritized(htmlNodeRenderer{goldmarkhtml.NewRenderer()}, 1000),
+					gutil.Prioritized(extension.NewTaskCheckBoxHTMLRenderer(), 500),
+				),
+			),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q: must be one of %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+// RenderNote writes the body of the note to w using the parser's renderer.
+// Links to other notes, including wiki links, are reduced to their text.
+func (p *Parser) RenderNote(w io.Writer, note *NoteContent, skipText []string) error {
+	source := []byte(note.Body)
+	root := p.md.Parser().Parse(text.NewReader(source))
+	pruneSkipText(root, source, skipText)
+
+	return p.renderer.Render(w, source, root)
+}
+
+// dialect describes the markup of a text based output format
+type dialect struct {
+	// heading returns the markup either side of a heading
+	heading func(level int) (string, string)
+	// bullet is the marker for unordered list items
+	bullet string
+	// indent is repeated for each level of list nesting
+	indent string
+	// link returns the markup either side of the text of a link
+	link func(destination string) (string, string)
+	// autoLink renders a bare URL
+	autoLink func(url string, label string) string
+	// emphasis and strong are the markup either side of emphasised text
+	emphasis string
+	strong   string
+	// escape escapes text for the format
+	escape func(string) string
+	// stripNoteLinks skips blocks and sections only holding links to other notes
+	stripNoteLinks bool
+}
+
+var commonMarkDialect = dialect{
+	heading: func(level int) (string, string) {
+		return strings.Repeat("#", level) + " ", ""
+	},
+	bullet: "*",
+	indent: "    ",
+	link: func(destination string) (string, string) {
+		return "[", "](" + destination + ")"
+	},
+	autoLink: func(url string, label string) string { return label },
+	emphasis: "*",
+	strong:   "**",
+	escape:   func(s string) string { return s },
+}
+
+var slackDialect = dialect{
+	heading: func(level int) (string, string) { return "*", "*" },
+	bullet:  "•",
+	indent:  "    ",
+	link: func(destination string) (string, string) {
+		return "<" + destination + "|", ">"
+	},
+	autoLink: func(url string, label string) string {
+		return "<" + url + "|" + slackEscaper.Replace(label) + ">"
+	},
+	emphasis:       "_",
+	strong:         "*",
+	escape:         slackEscaper.Replace,
+	stripNoteLinks: true,
+}
+
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+var teamsDialect = dialect{
+	heading: func(level int) (string, string) { return "**", "**" },
+	bullet:  "-",
+	indent:  "    ",
+	link: func(destination string) (string, string) {
+		return "[", "](" + destination + ")"
+	},
+	autoLink:       func(url string, label string) string { return label },
+	emphasis:       "_",
+	strong:         "**",
+	escape:         func(s string) string { return s },
+	stripNoteLinks: true,
+}
+
+var discordDialect = dialect{
+	heading: func(level int) (string, string) {
+		// discord only supports three levels of heading
+		if level > 3 {
+			return "**", "**"
+		}
+		return strings.Repeat("#", level) + " ", ""
+	},
+	bullet: "-",
+	indent: "  ",
+	link: func(destination string) (string, string) {
+		// angle brackets suppress the link preview embed
+		return "[", "](<" + destination + ">)"
+	},
+	autoLink:       func(url string, label string) string { return "<" + url + ">" },
+	emphasis:       "*",
+	strong:         "**",
+	escape:         func(s string) string { return s },
+	stripNoteLinks: true,
+}
+
+var plainDialect = dialect{
+	heading: func(level int) (string, string) { return "", "" },
+	bullet:  "-",
+	indent:  "  ",
+	link: func(destination string) (string, string) {
+		return "", " (" + destination + ")"
+	},
+	autoLink:       func(url string, label string) string { return label },
+	escape:         func(s string) string { return s },
+	stripNoteLinks: true,
+}
+
+// dialectRenderer renders the goldmark AST with the markup of a dialect
+type dialectRenderer struct {
+	dialect dialect
+	r       renderer.Renderer
+}
+
+func newDialectRenderer(d dialect) *dialectRenderer {
+	nr := &dialectNodeRenderer{dialect: d}
+	return &dialectRenderer{
+		dialect: d,
+		r:       renderer.NewRenderer(renderer.WithNodeRenderers(gutil.Prioritized(nr, 100))),
+	}
+}
+
+func (r *dialectRenderer) Render(w io.Writer, source []byte, n ast.Node) error {
+	if r.dialect.stripNoteLinks {
+		convertWikiLinks(n, source)
+	}
+	return r.r.Render(w, source, n)
+}
+
+func (r *dialectRenderer) Heading(level int, title string) string {
+	open, close := r.dialect.heading(level)
+	return open + r.dialect.escape(title) + close
+}
+
+// htmlRenderer renders the goldmark AST with the goldmark HTML renderer
+type htmlRenderer struct {
+	r renderer.Renderer
+}
+
+func (r *htmlRenderer) Render(w io.Writer, source []byte, n ast.Node) error {
+	convertWikiLinks(n, source)
+	return r.r.Render(w, source, n)
+}
+
+func (r *htmlRenderer) Heading(level int, title string) string {
+	return fmt.Sprintf("<h%d>%s</h%d>", level, html.EscapeString(title), level)
+}
+
+// htmlNodeRenderer is the goldmark HTML NodeRenderer with links to other
+// notes reduced to their text
+type htmlNodeRenderer struct {
+	renderer.NodeRenderer
+}
+
+func (r htmlNodeRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	r.NodeRenderer.RegisterFuncs(noteLinkRegisterer{reg})
+}
+
+// noteLinkRegisterer registers the link render function so it skips the
+// anchor of links to other notes, leaving their text, and the paragraph render
+// function so it skips paragraphs left empty by skip text or holding nothing
+// but links to other notes
+type noteLinkRegisterer struct {
+	renderer.NodeRendererFuncRegisterer
+}
+
+func (r noteLinkRegisterer) Register(kind ast.NodeKind, render renderer.NodeRendererFunc) {
+	if kind == ast.KindParagraph {
+		renderParagraph := render
+		render = func(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+			// exiting is still called for skipped nodes
+			if !hasContent(n, source) {
+				return ast.WalkSkipChildren, nil
+			}
+			return renderParagraph(w, source, n, entering)
+		}
+	}
+	if kind == ast.KindLink {
+		renderLink := render
+		render = func(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+			if !isURL(strings.TrimSpace(string(n.(*ast.Link).Destination))) {
+				return ast.WalkContinue, nil
+			}
+			return renderLink(w, source, n, entering)
+		}
+	}
+	r.NodeRendererFuncRegisterer.Register(kind, render)
+}
+
+// dialectNodeRenderer is a goldmark NodeRenderer producing the markup of a dialect
+type dialectNodeRenderer struct {
+	dialect dialect
+}
+
+func (r *dialectNodeRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindHeading, r.renderHeading)
+	reg.Register(ast.KindParagraph, r.renderParagraph)
+	reg.Register(ast.KindTextBlock, r.renderTextBlock)
+	reg.Register(ast.KindList, r.renderList)
+	reg.Register(ast.KindListItem, r.renderListItem)
+	reg.Register(ast.KindBlockquote, r.renderBlockquote)
+	reg.Register(ast.KindFencedCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindThematicBreak, r.renderThematicBreak)
+	reg.Register(ast.KindHTMLBlock, r.renderSkip)
+	reg.Register(ast.KindRawHTML, r.renderSkip)
+	reg.Register(ast.KindText, r.renderText)
+	reg.Register(ast.KindString, r.renderString)
+	reg.Register(ast.KindEmphasis, r.renderEmphasis)
+	reg.Register(ast.KindCodeSpan, r.renderCodeSpan)
+	reg.Register(ast.KindLink, r.renderLink)
+	reg.Register(ast.KindAutoLink, r.renderAutoLink)
+	reg.Register(ast.KindImage, r.renderImage)
+	reg.Register(extast.KindTaskCheckBox, r.renderTaskCheckBox)
+}
+
+func (r *dialectNodeRenderer) renderHeading(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	heading := n.(*ast.Heading)
+	// exiting is still called for skipped nodes
+	if r.dialect.stripNoteLinks && isEmptySection(heading, source) {
+		return ast.WalkSkipChildren, nil
+	}
+	open, close := r.dialect.heading(heading.Level)
+	if entering {
+		_, _ = w.WriteString(open)
+	} else {
+		_, _ = w.WriteString(close + "\n\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *dialectNodeRenderer) renderParagraph(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if r.dialect.stripNoteLinks && !hasContent(n, source) {
+		return ast.WalkSkipChildren, nil
+	}
+	if !entering {
+		_, _ = w.WriteString("\n")
+		if !isParentKind(n, ast.KindListItem) {
+			_, _ = w.WriteString("\n")
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *dialectNodeRenderer) renderTextBlock(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		_, _ = w.WriteString("\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *dialectNodeRenderer) renderList(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering && listDepth(n) == 1 {
+		_, _ = w.WriteString("\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *dialectNodeRenderer) renderListItem(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	if r.dialect.stripNoteLinks && !hasContent(n, source) {
+		return ast.WalkSkipChildren, nil
+	}
+
+	list := n.Parent().(*ast.List)
+	_, _ = w.WriteString(strings.Repeat(r.dialect.indent, listDepth(list)-1))
+	if list.IsOrdered() {
+		index := list.Start
+		for sibling := n.PreviousSibling(); sibling != nil; sibling = sibling.PreviousSibling() {
+			index++
+		}
+		_, _ = fmt.Fprintf(w, "%d. ", index)
+	} else {
+		_, _ = w.WriteString(r.dialect.bullet + " ")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *dialectNodeRenderer) renderBlockquote(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("> ")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *dialectNodeRenderer) renderCodeBlock(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("```")
+		if code, ok := n.(*ast.FencedCodeBlock); ok && code.Info != nil {
+			_, _ = w.Write(code.Info.Segment.Value(source))
+		}
+		_, _ = w.WriteString("\n")
+		lines := n.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			line := lines.At(i)
+			_, _ = w.Write(line.Value(source))
+		}
+		_, _ = w.WriteString("```\n\n")
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *dialectNodeRenderer) renderThematicBreak(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *dialectNodeRenderer) renderSkip(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *dialectNodeRenderer) renderText(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	node := n.(*ast.Text)
+	_, _ = w.WriteString(r.dialect.escape(string(node.Segment.Value(source))))
+	if node.HardLineBreak() || node.SoftLineBreak() && isInBlockquote(n) {
+		_, _ = w.WriteString("\n")
+		if isInBlockquote(n) {
+			_, _ = w.WriteString("> ")
+		}
+	} else if node.SoftLineBreak() {
+		_, _ = w.WriteString(" ")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *dialectNodeRenderer) renderString(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(r.dialect.escape(string(n.(*ast.String).Value)))
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *dialectNodeRenderer) renderEmphasis(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if n.(*ast.Emphasis).Level == 2 {
+		_, _ = w.WriteString(r.dialect.strong)
+	} else {
+		_, _ = w.WriteString(r.dialect.emphasis)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *dialectNodeRenderer) renderCodeSpan(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	_, _ = w.WriteString("`")
+	return ast.WalkContinue, nil
+}
+
+func (r *dialectNodeRenderer) renderLink(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	destination := strings.TrimSpace(string(n.(*ast.Link).Destination))
+	// links to other notes are reduced to their text
+	if !isURL(destination) {
+		return ast.WalkContinue, nil
+	}
+	open, close := r.dialect.link(destination)
+	if entering {
+		_, _ = w.WriteString(open)
+	} else {
+		_, _ = w.WriteString(close)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *dialectNodeRenderer) renderAutoLink(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		link := n.(*ast.AutoLink)
+		_, _ = w.WriteString(r.dialect.autoLink(string(link.URL(source)), string(link.Label(source))))
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *dialectNodeRenderer) renderImage(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		image := n.(*ast.Image)
+		open, close := r.dialect.link(string(image.Destination))
+		_, _ = w.WriteString(open + r.dialect.escape(string(image.Text(source))) + close)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *dialectNodeRenderer) renderTaskCheckBox(w gutil.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		if n.(*extast.TaskCheckBox).IsChecked {
+			_, _ = w.WriteString("[x] ")
+		} else {
+			_, _ = w.WriteString("[ ] ")
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+// pruneSkipText removes text nodes matching skipText from the tree
+func pruneSkipText(root ast.Node, source []byte, skipText []string) {
+	if len(skipText) == 0 {
+		return
+	}
+
+	var skipped []ast.Node
+	_ = ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && n.Kind() == ast.KindText {
+			if slices.Contains(skipText, string(n.(*ast.Text).Segment.Value(source))) {
+				skipped = append(skipped, n)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, n := range skipped {
+		n.Parent().RemoveChild(n.Parent(), n)
+	}
+}
+
+// wikiLinkRegex matches wiki links such as [[2024-12-11]] and
+// [[2024-12-11|Yesterday]]
+var wikiLinkRegex = regexp.MustCompile(`\[\[([^\[\]|\n]+)(?:\|([^\[\]\n]+))?\]\]`)
+
+// convertWikiLinks replaces the wiki links within n, which goldmark leaves as
+// text, with links to the notes they name, so they are rendered as any other
+// link to a note is
+func convertWikiLinks(n ast.Node, source []byte) {
+	var parents []ast.Node
+	seen := make(map[ast.Node]bool)
+	_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch child.Kind() {
+		case ast.KindCodeSpan, ast.KindLink:
+			return ast.WalkSkipChildren, nil
+		case ast.KindText:
+			if parent := child.Parent(); !seen[parent] {
+				seen[parent] = true
+				parents = append(parents, parent)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, parent := range parents {
+		// a wiki link may be split across text nodes following each other
+		// in the source
+		var run []*ast.Text
+		for child := parent.FirstChild(); child != nil; child = child.NextSibling() {
+			t, ok := child.(*ast.Text)
+			if ok && len(run) > 0 {
+				last := run[len(run)-1]
+				if last.Segment.Stop == t.Segment.Start && !last.SoftLineBreak() && !last.HardLineBreak() {
+					run = append(run, t)
+					continue
+				}
+			}
+			replaceWikiLinks(parent, run, source)
+			run = nil
+			if ok {
+				run = append(run, t)
+			}
+		}
+		replaceWikiLinks(parent, run, source)
+	}
+}
+
+// replaceWikiLinks replaces the text nodes of run, which follow each other in
+// the source, with text and links for the wiki links within them
+func replaceWikiLinks(parent ast.Node, run []*ast.Text, source []byte) {
+	if len(run) == 0 {
+		return
+	}
+	start, stop := run[0].Segment.Start, run[len(run)-1].Segment.Stop
+	matches := wikiLinkRegex.FindAllSubmatchIndex(source[start:stop], -1)
+	if matches == nil {
+		return
+	}
+
+	pos := start
+	for _, match := range matches {
+		if start+match[0] > pos {
+			parent.InsertBefore(parent, run[0], ast.NewTextSegment(text.NewSegment(pos, start+match[0])))
+		}
+		target := bytes.TrimSpace(source[start+match[2] : start+match[3]])
+		label := target
+		if match[4] >= 0 {
+			label = bytes.TrimSpace(source[start+match[4] : start+match[5]])
+		}
+		link := ast.NewLink()
+		link.Destination = target
+		link.AppendChild(link, ast.NewString(label))
+		parent.InsertBefore(parent, run[0], link)
+		pos = start + match[1]
+	}
+
+	// the remaining text keeps the line break ending the run
+	last := run[len(run)-1]
+	if pos < stop || last.SoftLineBreak() || last.HardLineBreak() {
+		rest := ast.NewTextSegment(text.NewSegment(pos, stop))
+		rest.SetSoftLineBreak(last.SoftLineBreak())
+		rest.SetHardLineBreak(last.HardLineBreak())
+		parent.InsertBefore(parent, run[0], rest)
+	}
+	for _, t := range run {
+		parent.RemoveChild(parent, t)
+	}
+}
+
+// hasContent reports whether the block holds anything other than links to
+// other notes
+func hasContent(n ast.Node, source []byte) bool {
+	content := false
+	_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch child.Kind() {
+		case ast.KindLink:
+			if isURL(strings.TrimSpace(string(child.(*ast.Link).Destination))) {
+				content = true
+				return ast.WalkStop, nil
+			}
+			return ast.WalkSkipChildren, nil
+		case ast.KindText:
+			if len(strings.TrimSpace(string(child.(*ast.Text).Segment.Value(source)))) > 0 {
+				content = true
+				return ast.WalkStop, nil
+			}
+		case ast.KindAutoLink, ast.KindImage, ast.KindCodeSpan, ast.KindFencedCodeBlock, ast.KindCodeBlock:
+			content = true
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return content
+}
+
+// isEmptySection reports whether nothing but links to other notes lies between
+// the heading and the next heading of the same or a higher level
+func isEmptySection(heading *ast.Heading, source []byte) bool {
+	for sibling := heading.NextSibling(); sibling != nil; sibling = sibling.NextSibling() {
+		if next, ok := sibling.(*ast.Heading); ok {
+			if next.Level <= heading.Level {
+				return true
+			}
+			continue
+		}
+		if hasContent(sibling, source) {
+			return false
+		}
+	}
+	return true
+}
+
+// listDepth returns the nesting depth of the list, starting at 1
+func listDepth(list ast.Node) int {
+	depth := 0
+	for n := list; n != nil; n = n.Parent() {
+		if n.Kind() == ast.KindList {
+			depth++
+		}
+	}
+	return depth
+}
+
+// isInBlockquote reports whether the node is nested within a blockquote
+func isInBlockquote(n ast.Node) bool {
+	for parent := n.Parent(); parent != nil; parent = parent.Parent() {
+		if parent.Kind() == ast.KindBlockquote {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderNoteReducesNoteLinks(t *testing.T) {
+	note := &NoteContent{Body: "* see [yesterday](2024-12-11.md) and [PLA-1](https://linear.app/acme/issue/PLA-1)\n"}
+
+	want := map[string]string{
+		FormatCommonMark: "* see yesterday and [PLA-1](https://linear.app/acme/issue/PLA-1)\n\n",
+		FormatSlack:      "• see yesterday and <https://linear.app/acme/issue/PLA-1|PLA-1>\n\n",
+		FormatPlain:      "- see yesterday and PLA-1 (https://linear.app/acme/issue/PLA-1)\n\n",
+		FormatHTML:       "<ul>\n<li>see yesterday and <a href=\"https://linear.app/acme/issue/PLA-1\">PLA-1</a></li>\n</ul>\n",
+	}
+	for format, want := range want {
+		t.Run(format, func(t *testing.T) {
+			renderer, err := NewRenderer(format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var sb strings.Builder
+			if err := NewParser(WithRenderer(renderer)).RenderNote(&sb, note, nil); err != nil {
+				t.Fatal(err)
+			}
+			if got := sb.String(); got != want {
+				t.Errorf("RenderNote() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestRenderNoteHTMLSkipsEmptyParagraphs(t *testing.T) {
+	note := &NoteContent{Body: "[Yesterday](2024-12-11.md)\n\nNone\n\nWorked on caching\n\n* [Tomorrow](2024-12-13.md)\n"}
+
+	renderer, err := NewRenderer(FormatHTML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := NewParser(WithRenderer(renderer)).RenderNote(&sb, note, []string{"None"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "<p>Worked on caching</p>\n<ul>\n<li>Tomorrow</li>\n</ul>\n"
+	if got := sb.String(); got != want {
+		t.Errorf("RenderNote() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderNoteReducesWikiLinks(t *testing.T) {
+	note := &NoteContent{Body: "[[2024-12-11|Yesterday]]\n\n* see [[2024-12-11]] and [[notes/ideas | ideas]]\n* ran `[[ -e x ]]` in [[a [b] c]]\n"}
+
+	want := map[string]string{
+		FormatCommonMark: "[[2024-12-11|Yesterday]]\n\n* see [[2024-12-11]] and [[notes/ideas | ideas]]\n* ran `[[ -e x ]]` in [[a [b] c]]\n\n",
+		FormatSlack:      "• see 2024-12-11 and ideas\n• ran `[[ -e x ]]` in [[a [b] c]]\n\n",
+		FormatPlain:      "- see 2024-12-11 and ideas\n- ran `[[ -e x ]]` in [[a [b] c]]\n\n",
+		FormatHTML:       "<ul>\n<li>see 2024-12-11 and ideas</li>\n<li>ran <code>[[ -e x ]]</code> in [[a [b] c]]</li>\n</ul>\n",
+	}
+	for format, want := range want {
+		t.Run(format, func(t *testing.T) {
+			renderer, err := NewRenderer(format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var sb strings.Builder
+			if err := NewParser(WithRenderer(renderer)).RenderNote(&sb, note, nil); err != nil {
+				t.Fatal(err)
+			}
+			if got := sb.String(); got != want {
+				t.Errorf("RenderNote() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestParseNoteContentSlackSectionsReduceWikiLinks(t *testing.T) {
+	renderer, err := NewRenderer(FormatSlack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, err := NewParser(WithRenderer(renderer)).ParseNoteContent("# Standup\n\n## Today\n\n* review [[2024-12-11|yesterday]]'s PR\n", nil, NoteTypeStandup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "• review yesterday's PR\n"
+	if got := note.Sections[1].Content; got != want {
+		t.Errorf("got section content %q, want %q", got, want)
+	}
+}
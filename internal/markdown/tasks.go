@@ -0,0 +1,49 @@
+package markdown
+
+import "time"
+
+// Task is a GFM task list item such as "* [ ] Look into why..."
+type Task struct {
+	// Text of the task, without the list marker and checkbox
+	Text string `json:"text" yaml:"text"`
+	// Checked is true for completed tasks ("[x]")
+	Checked bool `json:"checked" yaml:"checked"`
+	// Depth of list nesting of the task, starting at 1
+	Depth int `json:"depth" yaml:"depth"`
+	// Section is the title of the section holding the task
+	Section string `json:"section" yaml:"section"`
+	// Type of the note holding the task
+	Type NoteType `json:"type" yaml:"type"`
+	// Note is the name of the note holding the task, when known
+	Note string `json:"note" yaml:"note"`
+	// Date of the note holding the task, when known
+	Date time.Time `json:"date" yaml:"date"`
+	// Line number of the task within the content the note was parsed from,
+	// starting at 1
+	Line int `json:"line" yaml:"line"`
+	// Offset is the byte offset of the checkbox within the content the note
+	// was parsed from
+	Offset int `json:"offset" yaml:"offset"`
+}
+
+// tasksOf returns the task list items of items
+func tasksOf(items []ListItem) []Task {
+	tasks := make([]Task, 0)
+	for _, item := range items {
+		if !item.Task {
+			continue
+		}
+		tasks = append(tasks, Task{
+			Text:    item.Text,
+			Checked: item.Checked,
+			Depth:   item.Depth,
+			Section: item.Section,
+			Type:    item.Type,
+			Note:    item.Note,
+			Date:    item.Date,
+			Line:    item.Line,
+			Offset:  item.Offset,
+		})
+	}
+	return tasks
+}
@@ -0,0 +1,79 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTasks(t *testing.T) {
+	content := "# Daily\n\n## Goals\n\n* [ ] Review\n* [x] Deploy\n    * [X] Tag release\n    * [ ] Announce\n1. [ ] First\n2. [x] Second\n* [>] Moved\n- [ ] Dash\n\n## Notes\n\n* plain\n"
+	note, err := NewParser().ParseNoteContent(content, nil, NoteTypeJournal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Task{
+		{Text: "Review", Depth: 1, Line: 5},
+		{Text: "Deploy", Checked: true, Depth: 1, Line: 6},
+		{Text: "Tag release", Checked: true, Depth: 2, Line: 7},
+		{Text: "Announce", Depth: 2, Line: 8},
+		{Text: "First", Depth: 1, Line: 9},
+		{Text: "Second", Checked: true, Depth: 1, Line: 10},
+		// "[>]" is not a checkbox, so rolled over tasks are plain items
+		{Text: "Dash", Depth: 1, Line: 12},
+	}
+	for i := range want {
+		want[i].Section = "Goals"
+		want[i].Type = NoteTypeJournal
+	}
+
+	got := make([]Task, len(note.Tasks))
+	for i, task := range note.Tasks {
+		if !strings.HasPrefix(content[task.Offset:], "[") {
+			t.Errorf("task %q offset at %q, want its checkbox", task.Text, content[task.Offset:])
+		}
+		task.Offset = 0
+		got[i] = task
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got tasks\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestDropCompletedTasks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "bullet",
+			content: "* [ ] Review\n* [x] Deploy\n* [X] Tag\n- [x] Dash\n+ [ ] Plus",
+			want:    "* [ ] Review\n+ [ ] Plus",
+		},
+		{
+			name:    "nested",
+			content: "* [x] Deploy\n    * [ ] Announce\n    * notes\n* [ ] Review\n    * [x] Read\n    * [ ] Comment",
+			want:    "* [ ] Review\n    * [ ] Comment",
+		},
+		{
+			name:    "ordered",
+			content: "1. [ ] First\n2. [x] Second\n    * [ ] Sub\n3) [x] Third\n4. [ ] Fourth",
+			want:    "1. [ ] First\n4. [ ] Fourth",
+		},
+		{
+			name:    "not tasks",
+			content: "* [>] Moved\n* [x]no space\n[x] Not a list",
+			want:    "* [>] Moved\n* [x]no space\n[x] Not a list",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DropCompletedTasks(tt.content); got != tt.want {
+				t.Errorf("DropCompletedTasks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
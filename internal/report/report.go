@@ -0,0 +1,301 @@
+// Package report aggregates work done across notes into deduplicated summaries.
+package report
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/issues"
+)
+
+// ClientPattern matches inline markers which may name a client, such as #acme,
+// @acme or #company:acme. Only markers with a client prefix or naming one of
+// the known clients of a Collector are attributed to a client, so hashtags and
+// mentions such as #deploy or @alice are not taken for clients.
+var ClientPattern = regexp.MustCompile(`(?:^|\s)[#@]([A-Za-z][\w.-]*(?::[\w.-]+)?)`)
+
+// Entry is a top level list item of section content, along with the items
+// nested beneath it
+type Entry struct {
+	// Text of the item, without the list marker
+	Text string `json:"text" yaml:"text"`
+	// Children are the nested lines beneath the item, with their indentation
+	Children []string `json:"children,omitempty" yaml:"children,omitempty"`
+	// Dates of the notes the item appeared in
+	Dates []time.Time `json:"dates" yaml:"dates"`
+	// Issues referenced by the item
+	Issues []string `json:"issues,omitempty" yaml:"issues,omitempty"`
+	// Clients the item is attributed to
+	Clients []string `json:"clients,omitempty" yaml:"clients,omitempty"`
+}
+
+// Collector accumulates entries across notes, merging repeated entries
+type Collector struct {
+	// Clients are the known client names, which may be marked inline without
+	// a client prefix (e.g #acme)
+	Clients []string
+
+	entries []*Entry
+	index   map[string]*Entry
+
+	// matcher finds the issues referenced by entries
+	matcher *issues.Matcher
+	// clientPrefixes are stripped from tags and markers to give client names
+	clientPrefixes []string
+}
+
+// NewCollector returns an empty Collector finding the issues referenced by
+// entries with matcher (default issues.DefaultMatcher). Client names are given
+// by tags and markers with any of clientPrefixes (e.g company:) removed.
+func NewCollector(matcher *issues.Matcher, clientPrefixes ...string) *Collector {
+	if matcher == nil {
+		matcher = issues.DefaultMatcher()
+	}
+	return &Collector{index: make(map[string]*Entry), matcher: matcher, clientPrefixes: clientPrefixes}
+}
+
+// Add collects the list items of section content from the note dated date.
+// Items are attributed to the clients marked inline with a client prefix (e.g
+// #company:acme) or a known client name (e.g @acme), or else to the clients
+// the note is attributed to.
+func (c *Collector) Add(date time.Time, content string, clients ...string) {
+	var current *Entry
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			if current != nil && !slices.Contains(current.Children, line) {
+				current.Children = append(current.Children, line)
+			}
+			continue
+		}
+
+		text := listMarkerRegex.ReplaceAllString(line, "")
+		key := normalize(text)
+
+		entry, ok := c.index[key]
+		if !ok {
+			entry = &Entry{Text: text}
+			entry.Issues = c.matcher.FindKeys(text)
+			c.index[key] = entry
+			c.entries = append(c.entries, entry)
+		}
+
+		itemClients := c.inlineClients(text)
+		if len(itemClients) == 0 {
+			itemClients = clients
+		}
+		for _, client := range itemClients {
+			client = c.ClientName(client)
+			if client != "" && !slices.Contains(entry.Clients, client) {
+				entry.Clients = append(entry.Clients, client)
+			}
+		}
+		if !slices.ContainsFunc(entry.Dates, date.Equal) {
+			entry.Dates = append(entry.Dates, date)
+		}
+		current = entry
+	}
+}
+
+// inlineClients returns the clients marked within text
+func (c *Collector) inlineClients(text string) []string {
+	var clients []string
+	for _, match := range ClientPattern.FindAllStringSubmatch(text, -1) {
+		// trailing punctuation ends a sentence rather than the marker
+		marker := strings.ToLower(strings.TrimRight(match[1], ".-"))
+		if c.hasClientPrefix(marker) || slices.ContainsFunc(c.Clients, func(client string) bool {
+			return strings.EqualFold(client, marker)
+		}) {
+			clients = append(clients, marker)
+		}
+	}
+	return clients
+}
+
+// hasClientPrefix reports whether the lower cased tag starts with a client prefix
+func (c *Collector) hasClientPrefix(tag string) bool {
+	for _, prefix := range c.clientPrefixes {
+		if strings.HasPrefix(tag, strings.ToLower(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientName returns the client named by a tag or marker, lower cased and
+// with any client prefix removed
+func (c *Collector) ClientName(tag string) string {
+	tag = strings.ToLower(strings.TrimLeft(tag, "#@"))
+	for _, prefix := range c.clientPrefixes {
+		if name, ok := strings.CutPrefix(tag, strings.ToLower(prefix)); ok {
+			return name
+		}
+	}
+	return tag
+}
+
+// ClientTags returns the clients named by the tags with a client prefix
+func (c *Collector) ClientTags(tags []string) []string {
+	var clients []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimLeft(tag, "#"))
+		if c.hasClientPrefix(tag) {
+			clients = append(clients, c.ClientName(tag))
+		}
+	}
+	return clients
+}
+
+// Entries returns the collected entries in the order they were first seen
+func (c *Collector) Entries() []*Entry {
+	return c.entries
+}
+
+// IssueGroup is the set of entries referencing an issue
+type IssueGroup struct {
+	// Issue key, such as PLA-77 or org/repo#123
+	Issue string `json:"issue" yaml:"issue"`
+	// Entries referencing the issue
+	Entries []*Entry `json:"entries" yaml:"entries"`
+}
+
+// Grouped returns the entries grouped by the first issue they reference, in
+// the order the issues were first seen, along with the entries referencing no
+// issue
+func (c *Collector) Grouped() ([]IssueGroup, []*Entry) {
+	var groups []IssueGroup
+	other := make([]*Entry, 0)
+	index := make(map[string]int)
+
+	for _, entry := range c.entries {
+		if len(entry.Issues) == 0 {
+			other = append(other, entry)
+			continue
+		}
+
+		issue := entry.Issues[0]
+		i, ok := index[issue]
+		if !ok {
+			i = len(groups)
+			index[issue] = i
+			groups = append(groups, IssueGroup{Issue: issue})
+		}
+		groups[i].Entries = append(groups[i].Entries, entry)
+	}
+
+	return groups, other
+}
+
+// ClientGroup is the set of entries attributed to a client
+type ClientGroup struct {
+	// Client name, such as acme
+	Client string `json:"client" yaml:"client"`
+	// Entries attributed to the client
+	Entries []*Entry `json:"entries" yaml:"entries"`
+}
+
+// ByClient returns the entries grouped by client, sorted by client name, along
+// with the entries attributed to no client. Entries attributed to several
+// clients appear in each of their groups.
+func (c *Collector) ByClient() ([]ClientGroup, []*Entry) {
+	var groups []ClientGroup
+	other := make([]*Entry, 0)
+	index := make(map[string]int)
+
+	for _, entry := range c.entries {
+		if len(entry.Clients) == 0 {
+			other = append(other, entry)
+			continue
+		}
+
+		for _, client := range entry.Clients {
+			i, ok := index[client]
+			if !ok {
+				i = len(groups)
+				index[client] = i
+				groups = append(groups, ClientGroup{Client: client})
+			}
+			groups[i].Entries = append(groups[i].Entries, entry)
+		}
+	}
+
+	slices.SortFunc(groups, func(a, b ClientGroup) int {
+		return strings.Compare(a.Client, b.Client)
+	})
+	return groups, other
+}
+
+// WriteList writes entries as a CommonMark list, annotating each with the
+// dates it appeared on formatted with dateLayout. No dates are written when
+// dateLayout is empty.
+func WriteList(sb *strings.Builder, entries []*Entry, dateLayout string) {
+	for _, entry := range entries {
+		sb.WriteString("* " + entry.Text)
+		if dateLayout != "" {
+			dates := make([]string, 0, len(entry.Dates))
+			for _, date := range entry.Dates {
+				dates = append(dates, date.Format(dateLayout))
+			}
+			fmt.Fprintf(sb, " (%s)", strings.Join(dates, ", "))
+		}
+		sb.WriteString("\n")
+		for _, child := range entry.Children {
+			sb.WriteString(child + "\n")
+		}
+	}
+}
+
+// WriteGrouped writes the entries as CommonMark with a heading of the given
+// level per issue, followed by the entries referencing no issue
+func (c *Collector) WriteGrouped(sb *strings.Builder, level int, dateLayout string) {
+	groups, other := c.Grouped()
+	heading := strings.Repeat("#", level) + " "
+
+	for _, group := range groups {
+		sb.WriteString(heading + group.Issue + "\n\n")
+		WriteList(sb, group.Entries, dateLayout)
+		sb.WriteString("\n")
+	}
+
+	if len(other) > 0 {
+		if len(groups) > 0 {
+			sb.WriteString(heading + "Other\n\n")
+		}
+		WriteList(sb, other, dateLayout)
+		sb.WriteString("\n")
+	}
+}
+
+// WriteByClient writes the entries as CommonMark with a heading of the given
+// level per client, followed by the entries attributed to no client
+func (c *Collector) WriteByClient(sb *strings.Builder, level int, dateLayout string) {
+	groups, other := c.ByClient()
+	heading := strings.Repeat("#", level) + " "
+
+	for _, group := range groups {
+		sb.WriteString(heading + group.Client + "\n\n")
+		WriteList(sb, group.Entries, dateLayout)
+		sb.WriteString("\n")
+	}
+
+	if len(other) > 0 {
+		if len(groups) > 0 {
+			sb.WriteString(heading + "Unattributed\n\n")
+		}
+		WriteList(sb, other, dateLayout)
+		sb.WriteString("\n")
+	}
+}
+
+var listMarkerRegex = regexp.MustCompile(`^(?:[*+-]|\d+[.)])\s+`)
+
+// normalize returns the key used to detect repeated entries
+func normalize(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
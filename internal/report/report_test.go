@@ -0,0 +1,40 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCollectorClients(t *testing.T) {
+	date := time.Date(2024, 12, 12, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		clients []string
+		text    string
+		want    []string
+	}{
+		{name: "prefixed marker", text: "* Fixed SSO for #company:acme", want: []string{"acme"}},
+		{name: "prefixed mention", text: "* Fixed SSO for @client:Acme.", want: []string{"acme"}},
+		{name: "hashtags and mentions", text: "* #deploy with @alice, see #123", want: []string{"globex"}},
+		{name: "known client", clients: []string{"Acme"}, text: "* Fixed SSO for #acme and @alice", want: []string{"acme"}},
+		{name: "marker within a word", clients: []string{"acme"}, text: "* Emailed ops#acme", want: []string{"globex"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCollector(nil, "company:", "client:")
+			c.Clients = tt.clients
+			c.Add(date, tt.text, c.ClientTags([]string{"company:globex", "journal"})...)
+
+			entries := c.Entries()
+			if len(entries) != 1 {
+				t.Fatalf("got %d entries, want 1", len(entries))
+			}
+			if got := entries[0].Clients; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Clients = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Package slack posts messages to Slack incoming webhooks using Block Kit.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxHeaderLength is the maximum length of the text of a header block
+	maxHeaderLength = 150
+	// maxSectionLength is the maximum length of the text of a section block
+	maxSectionLength = 3000
+)
+
+// Message is the payload posted to an incoming webhook
+type Message struct {
+	// Text is shown in notifications
+	Text string `json:"text" yaml:"text"`
+	// Blocks make up the body of the message
+	Blocks []Block `json:"blocks" yaml:"blocks"`
+}
+
+// Block is a Block Kit layout block
+type Block struct {
+	Type string `json:"type" yaml:"type"`
+	Text *Text  `json:"text,omitempty" yaml:"text,omitempty"`
+}
+
+// Text is a Block Kit text object
+type Text struct {
+	Type string `json:"type" yaml:"type"`
+	Text string `json:"text" yaml:"text"`
+}
+
+// escaper escapes the control characters of mrkdwn text
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Section is a titled piece of mrkdwn content. The title is plain text, the
+// content is mrkdwn.
+type Section struct {
+	Title   string
+	Content string
+}
+
+// NewMessage builds a message with a header block for the title followed by
+// the sections. Sections too long for a single block are split across blocks
+// at line boundaries.
+func NewMessage(title string, sections []Section) Message {
+	msg := Message{Text: title}
+
+	if title != "" {
+		msg.Blocks = append(msg.Blocks, Block{
+			Type: "header",
+			Text: &Text{Type: "plain_text", Text: truncate(title, maxHeaderLength)},
+		})
+	}
+
+	for _, section := range sections {
+		text := strings.TrimSpace(section.Content)
+		if section.Title != "" {
+			text = "*" + escaper.Replace(section.Title) + "*\n" + text
+		}
+		for _, chunk := range splitLines(text, maxSectionLength) {
+			msg.Blocks = append(msg.Blocks, Block{
+				Type: "section",
+				Text: &Text{Type: "mrkdwn", Text: chunk},
+			})
+		}
+	}
+
+	return msg
+}
+
+// Client posts messages to an incoming webhook
+type Client struct {
+	// WebhookURL is the URL of the incoming webhook
+	WebhookURL string
+	// HTTPClient sends the requests
+	HTTPClient *http.Client
+	// MaxRetries is the number of times a rate limited or failed request is retried
+	MaxRetries int
+	// Backoff is the delay before the first retry, doubled on each further retry.
+	// A Retry-After header on a rate limited response takes precedence.
+	Backoff time.Duration
+	// DryRun skips sending messages
+	DryRun bool
+}
+
+// NewClient returns a Client for the incoming webhook
+func NewClient(webhookURL string) *Client {
+	return &Client{
+		WebhookURL: webhookURL,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		MaxRetries: 3,
+		Backoff:    time.Second,
+	}
+}
+
+// Post sends the message to the webhook, retrying on 429 and 5xx responses.
+// Nothing is sent during a dry run.
+func (c *Client) Post(ctx context.Context, msg Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if c.DryRun {
+		return nil
+	}
+
+	backoff := c.Backoff
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := c.post(ctx, payload)
+		if err == nil {
+			return nil
+		}
+		if retryAfter < 0 || attempt >= c.MaxRetries {
+			return err
+		}
+
+		delay := backoff
+		if retryAfter > 0 {
+			delay = retryAfter
+		}
+		backoff *= 2
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// post sends a single request. The returned duration is negative when the
+// request must not be retried, otherwise it is the delay requested by the
+// server, or zero.
+func (c *Client) post(ctx context.Context, payload []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.WebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return -1, err
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return retryAfter(resp), fmt.Errorf("slack webhook rate limited: %s", resp.Status)
+	case resp.StatusCode >= 500:
+		return 0, fmt.Errorf("slack webhook failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	default:
+		return -1, fmt.Errorf("slack webhook rejected message: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+}
+
+// retryAfter returns the delay requested by the Retry-After header, or zero
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// truncate shortens s to at most n runes
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// splitLines splits s into chunks of at most n bytes, breaking between lines
+// where possible
+func splitLines(s string, n int) []string {
+	var chunks []string
+	var chunk strings.Builder
+	for _, line := range strings.SplitAfter(s, "\n") {
+		for len(line) > n {
+			if chunk.Len() > 0 {
+				chunks = append(chunks, strings.TrimRight(chunk.String(), "\n"))
+				chunk.Reset()
+			}
+			cut := n
+			for cut > 0 && (line[cut]&0xC0) == 0x80 {
+				cut--
+			}
+			chunks = append(chunks, line[:cut])
+			line = line[cut:]
+		}
+		if chunk.Len()+len(line) > n {
+			chunks = append(chunks, strings.TrimRight(chunk.String(), "\n"))
+			chunk.Reset()
+		}
+		chunk.WriteString(line)
+	}
+	if rest := strings.TrimRight(chunk.String(), "\n"); rest != "" {
+		chunks = append(chunks, rest)
+	}
+	return chunks
+}
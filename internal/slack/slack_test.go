@@ -0,0 +1,180 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client posting to a server handling each request
+// with handler, given the number of the attempt starting at 1, along with the
+// number of requests the server received
+func newTestClient(t *testing.T, handler func(attempt int, w http.ResponseWriter, r *http.Request)) (*Client, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(int(requests.Add(1)), w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL)
+	client.HTTPClient = server.Client()
+	client.Backoff = 10 * time.Millisecond
+	return client, &requests
+}
+
+func TestNewMessage(t *testing.T) {
+	msg := NewMessage("Standup 2024-12-12", []Section{
+		{Title: "Worked on Yesterday", Content: "• Fixed SSO\n"},
+		{Title: "R&D <infra>", Content: "• Looked into caching"},
+	})
+
+	want := Message{
+		Text: "Standup 2024-12-12",
+		Blocks: []Block{
+			{Type: "header", Text: &Text{Type: "plain_text", Text: "Standup 2024-12-12"}},
+			{Type: "section", Text: &Text{Type: "mrkdwn", Text: "*Worked on Yesterday*\n• Fixed SSO"}},
+			{Type: "section", Text: &Text{Type: "mrkdwn", Text: "*R&amp;D &lt;infra&gt;*\n• Looked into caching"}},
+		},
+	}
+
+	got, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(expected) {
+		t.Errorf("NewMessage() =\n%s\nwant\n%s", got, expected)
+	}
+}
+
+func TestNewMessageSplitsLongSections(t *testing.T) {
+	line := strings.Repeat("x", 99) + "\n"
+	msg := NewMessage("", []Section{{Content: strings.Repeat(line, 100)}})
+
+	if len(msg.Blocks) != 4 {
+		t.Fatalf("got %d blocks, want 4", len(msg.Blocks))
+	}
+	for _, block := range msg.Blocks {
+		if n := len(block.Text.Text); n > maxSectionLength {
+			t.Errorf("block of %d bytes exceeds %d", n, maxSectionLength)
+		}
+	}
+}
+
+func TestPostPayload(t *testing.T) {
+	msg := NewMessage("Standup", []Section{{Title: "Today", Content: "• SSO"}})
+
+	var got Message
+	client, requests := newTestClient(t, func(attempt int, w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+	})
+
+	if err := client.Post(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+	if got.Text != "Standup" || len(got.Blocks) != 2 || got.Blocks[0].Type != "header" || got.Blocks[1].Text.Text != "*Today*\n• SSO" {
+		t.Errorf("unexpected payload %+v", got)
+	}
+}
+
+func TestPostDryRun(t *testing.T) {
+	client, requests := newTestClient(t, func(attempt int, w http.ResponseWriter, r *http.Request) {})
+	client.DryRun = true
+
+	if err := client.Post(context.Background(), NewMessage("Standup", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("got %d requests during a dry run, want 0", n)
+	}
+}
+
+func TestPostRetryAfter(t *testing.T) {
+	client, requests := newTestClient(t, func(attempt int, w http.ResponseWriter, r *http.Request) {
+		if attempt == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	})
+
+	start := time.Now()
+	if err := client.Post(context.Background(), NewMessage("Standup", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+	// the Retry-After header takes precedence over the backoff
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least 1s", elapsed)
+	}
+}
+
+func TestPostRetriesServerErrors(t *testing.T) {
+	client, requests := newTestClient(t, func(attempt int, w http.ResponseWriter, r *http.Request) {
+		if attempt <= 3 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	})
+
+	start := time.Now()
+	if err := client.Post(context.Background(), NewMessage("Standup", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if n := requests.Load(); n != 4 {
+		t.Errorf("got %d requests, want 4", n)
+	}
+	// 10ms, 20ms then 40ms
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("retried within %s, want backoff of at least 70ms", elapsed)
+	}
+}
+
+func TestPostGivesUpAfterMaxRetries(t *testing.T) {
+	client, requests := newTestClient(t, func(attempt int, w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	client.MaxRetries = 2
+
+	if err := client.Post(context.Background(), NewMessage("Standup", nil)); err == nil {
+		t.Fatal("expected an error")
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("got %d requests, want 3", n)
+	}
+}
+
+func TestPostDoesNotRetryClientErrors(t *testing.T) {
+	client, requests := newTestClient(t, func(attempt int, w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("invalid_blocks"))
+	})
+
+	err := client.Post(context.Background(), NewMessage("Standup", nil))
+	if err == nil || !strings.Contains(err.Error(), "invalid_blocks") {
+		t.Fatalf("got error %v, want the rejection", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+}
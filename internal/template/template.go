@@ -0,0 +1,154 @@
+// Package template renders new notes from text/template files.
+//
+// Templates are executed with a Data value. Sections carried over from prior
+// notes are available through Data.Sections; standup notes provide the
+// "work_done" and "today" keys, journal notes and notes of other types provide
+// every section of the previous note of the same type keyed by its title.
+package template
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed templates/*.md.tmpl
+var builtin embed.FS
+
+// Template is a parsed note template
+type Template struct {
+	tmpl *template.Template
+}
+
+// New parses the template file at templatePath. When templatePath is empty the
+// built-in template for the named note type is used, falling back to a generic
+// template listing the configured section headings.
+func New(noteType string, templatePath string) (*Template, error) {
+	var (
+		content []byte
+		err     error
+	)
+	if templatePath == "" {
+		content, err = builtin.ReadFile(path.Join("templates", noteType+".md.tmpl"))
+		if errors.Is(err, fs.ErrNotExist) {
+			content, err = builtin.ReadFile("templates/note.md.tmpl")
+		}
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		content, err = os.ReadFile(templatePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tmpl, err := template.New(noteType).Funcs(funcs).Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Template{tmpl: tmpl}, nil
+}
+
+// Execute renders the template with data to w
+func (t *Template) Execute(w io.Writer, data Data) error {
+	return t.tmpl.Execute(w, data)
+}
+
+var funcs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"trim": strings.TrimSpace,
+}
+
+// Data is the data model templates are executed with
+type Data struct {
+	// Type of the note, such as journal or retro
+	Type string
+	// Date of the note
+	Date time.Time
+	// Name of the note relative to its notes directory, without extension
+	Name string
+	// Name of the previous note of the same type, without extension
+	Previous string
+	// Name of the next note of the same type, without extension
+	Next string
+	// Journal notes adjacent to Date
+	Journal Adjacent
+	// Standup notes adjacent to Date
+	Standup Adjacent
+	// Notes of every registered type adjacent to Date, keyed by type
+	Notes map[string]Adjacent
+	// Headings of the sections configured for the note type, in order
+	Headings []string
+	// PreviousTitle and NextTitle are the titles of links to the previous and
+	// next notes of the same type
+	PreviousTitle string
+	NextTitle     string
+	// Content carried over from prior notes keyed by section
+	Sections map[string]Section
+	// Front matter of the previous note of the same type
+	FrontMatter map[string]interface{}
+}
+
+// Link returns a link target for the named note of the same type relative to
+// the rendered note
+func (d Data) Link(name string) string {
+	return d.Notes[d.Type].Link(name)
+}
+
+// Section is a section of content carried over into the new note
+type Section struct {
+	// Title of the section
+	Title string
+	// Content of the section
+	Content string
+}
+
+// Adjacent holds the names of the notes of one type adjacent to a date
+type Adjacent struct {
+	// Directory of the notes relative to the directory of the rendered note
+	Dir string
+	// Name of the most recent note before the date, without extension
+	Previous string
+	// Name of the note for the date, without extension
+	Today string
+	// Name of the note for the day after the date, without extension
+	Next string
+
+	// noteDir and dir are the directories of the rendered note and of the
+	// adjacent notes, when known
+	noteDir string
+	dir     string
+}
+
+// NewAdjacent returns an Adjacent for the notes in dir, linked from a note
+// rendered into noteDir
+func NewAdjacent(noteDir string, dir string) (Adjacent, error) {
+	rel, err := filepath.Rel(noteDir, dir)
+	if err != nil {
+		return Adjacent{}, err
+	}
+	return Adjacent{Dir: rel, noteDir: noteDir, dir: dir}, nil
+}
+
+// Link returns a link target for the named note relative to the rendered note
+func (a Adjacent) Link(name string) string {
+	if a.noteDir != "" {
+		if rel, err := filepath.Rel(a.noteDir, filepath.Join(a.dir, filepath.FromSlash(name))); err == nil {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return path.Join(filepath.ToSlash(a.Dir), name)
+}
@@ -0,0 +1,162 @@
+package template
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/markdown"
+)
+
+// adjacent returns the adjacent notes of the note type held in dir for a
+// note rendered into noteDir
+func adjacent(t *testing.T, noteDir string, dir string, previous string) Adjacent {
+	t.Helper()
+	a, err := NewAdjacent(noteDir, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Previous = previous
+	a.Today = "2024-12-12"
+	a.Next = "2024-12-13"
+	return a
+}
+
+func TestBuiltinTemplates(t *testing.T) {
+	root := t.TempDir()
+	dirs := map[markdown.NoteType]string{
+		markdown.NoteTypeJournal: filepath.Join(root, "journal"),
+		markdown.NoteTypeStandup: filepath.Join(root, "standup"),
+		"retro":                  filepath.Join(root, "retro"),
+	}
+
+	tests := []struct {
+		noteType markdown.NoteType
+		// sections of the rendered note, in order
+		sections []string
+		// targets of the links to other notes, and the types they link to
+		links   map[string]markdown.NoteType
+		regions []string
+		title   string
+		tags    []string
+	}{
+		{
+			noteType: markdown.NoteTypeJournal,
+			sections: []string{"Daily Log 2024-12-12", "Goals of the Week", "Goals of the Day", "Worked On", "Work Completed", "Meetings", "Thoughts"},
+			links: map[string]markdown.NoteType{
+				"2024-12-11":            markdown.NoteTypeJournal,
+				"2024-12-13":            markdown.NoteTypeJournal,
+				"../standup/2024-12-12": markdown.NoteTypeStandup,
+			},
+			regions: []string{},
+			title:   "daily-2024-12-12",
+			tags:    []string{"daily"},
+		},
+		{
+			noteType: markdown.NoteTypeStandup,
+			sections: []string{"Standup 2024-12-12", "Work Done", "Today", "Blocked on", "Notes", "Links"},
+			links: map[string]markdown.NoteType{
+				"2024-12-11":            markdown.NoteTypeStandup,
+				"2024-12-13":            markdown.NoteTypeStandup,
+				"../journal/2024-12-11": markdown.NoteTypeJournal,
+				"../journal/2024-12-12": markdown.NoteTypeJournal,
+				"../journal/2024-12-13": markdown.NoteTypeJournal,
+			},
+			regions: []string{"work-done", "today"},
+			title:   "standup-2024-12-12",
+			tags:    []string{"standup"},
+		},
+		{
+			noteType: "retro",
+			sections: []string{"retro 2024-12-12", "Went Well", "To Improve"},
+			links: map[string]markdown.NoteType{
+				"2024-12-11": "retro",
+				"2024-12-13": "retro",
+			},
+			regions: []string{},
+			title:   "retro-2024-12-12",
+			tags:    []string{"retro"},
+		},
+	}
+
+	var opts []markdown.ParserOption
+	for noteType, dir := range dirs {
+		opts = append(opts, markdown.WithNoteDir(noteType, dir))
+	}
+	parser := markdown.NewParser(opts...)
+
+	for _, tt := range tests {
+		t.Run(string(tt.noteType), func(t *testing.T) {
+			noteDir := dirs[tt.noteType]
+			data := Data{
+				Type:          string(tt.noteType),
+				Date:          time.Date(2024, 12, 12, 0, 0, 0, 0, time.UTC),
+				Name:          "2024-12-12",
+				Previous:      "2024-12-11",
+				Next:          "2024-12-13",
+				Notes:         make(map[string]Adjacent),
+				Headings:      []string{"Went Well", "To Improve"},
+				PreviousTitle: "Previous",
+				NextTitle:     "Next",
+				Sections: map[string]Section{
+					"work_done":  {Title: "Work Done", Content: "* Deployed"},
+					"today":      {Title: "Today", Content: "* Review"},
+					"Went Well":  {Title: "Went Well", Content: "* Releases"},
+					"To Improve": {Title: "To Improve"},
+				},
+			}
+			for noteType, dir := range dirs {
+				data.Notes[string(noteType)] = adjacent(t, noteDir, dir, "2024-12-11")
+			}
+			data.Journal = data.Notes[string(markdown.NoteTypeJournal)]
+			data.Standup = data.Notes[string(markdown.NoteTypeStandup)]
+
+			tmpl, err := New(string(tt.noteType), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			if err := tmpl.Execute(&out, data); err != nil {
+				t.Fatal(err)
+			}
+
+			note, err := parser.ParseNoteContent(out.String(), nil, tt.noteType, markdown.WithNoteName(data.Name))
+			if err != nil {
+				t.Fatalf("parsing the rendered note: %v\n%s", err, out.String())
+			}
+
+			sections := make([]string, 0)
+			for _, section := range note.Sections {
+				sections = append(sections, section.Title)
+			}
+			if !reflect.DeepEqual(sections, tt.sections) {
+				t.Errorf("got sections %v, want %v", sections, tt.sections)
+			}
+
+			links := make(map[string]markdown.NoteType)
+			for _, link := range note.AdjacentLinks {
+				links[link.Target] = link.TargetNoteType
+			}
+			if !reflect.DeepEqual(links, tt.links) {
+				t.Errorf("got links %v, want %v", links, tt.links)
+			}
+
+			regions := make([]string, 0)
+			for _, region := range note.Regions {
+				regions = append(regions, region.Name)
+			}
+			if !reflect.DeepEqual(regions, tt.regions) || note.MarkerError != nil {
+				t.Errorf("got regions %v with marker error %v, want %v", regions, note.MarkerError, tt.regions)
+			}
+
+			if note.FrontMatter.Title != tt.title {
+				t.Errorf("got title %q, want %q", note.FrontMatter.Title, tt.title)
+			}
+			if !reflect.DeepEqual(note.FrontMatter.Tags, tt.tags) {
+				t.Errorf("got tags %v, want %v", note.FrontMatter.Tags, tt.tags)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package util
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// FilenameFormat describes how notes are named after their date, as a path
+// relative to the directory holding the notes
+type FilenameFormat struct {
+	// layout is the Go time layout of the path, including the .md extension
+	layout string
+}
+
+// DefaultFilenameFormat names notes YYYY-MM-DD.md
+var DefaultFilenameFormat = &FilenameFormat{layout: "2006-01-02.md"}
+
+// ParseFilenameFormat parses a filename format given as a Go time layout such
+// as 2006/01/2006-01-02.md, or a strftime pattern such as %Y/%m/%Y-%m-%d.md.
+// The .md extension is added when missing. Formats with literal text that
+// would be read as a time of day or zone, such as the 4 of notes-v4-2006-01-02,
+// are rejected.
+func ParseFilenameFormat(format string) (*FilenameFormat, error) {
+	if format == "" {
+		return DefaultFilenameFormat, nil
+	}
+
+	layout := format
+	if strings.Contains(format, "%") {
+		var err error
+		layout, err = strftimeToLayout(format)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !strings.HasSuffix(layout, ".md") {
+		layout += ".md"
+	}
+	if path.IsAbs(layout) || strings.HasPrefix(path.Clean(layout), "..") {
+		return nil, fmt.Errorf("invalid filename format %q: must be relative to the notes directory", format)
+	}
+
+	// the layout must round trip a date to be usable for discovery
+	reference := time.Date(2024, time.December, 12, 0, 0, 0, 0, time.UTC)
+	parsed, err := time.Parse(layout, reference.Format(layout))
+	if err != nil || !parsed.Equal(reference) {
+		return nil, fmt.Errorf("invalid filename format %q: must include the year, month and day", format)
+	}
+
+	// notes are named after their date alone, so any element of the time of
+	// day is literal text such as the 4 of notes-v4-2006-01-02
+	if timeOfDayCheckTimes[0].Format(layout) != timeOfDayCheckTimes[1].Format(layout) {
+		return nil, fmt.Errorf("invalid filename format %q: literal text would be read as a time of day or zone", format)
+	}
+
+	return &FilenameFormat{layout: layout}, nil
+}
+
+// Format returns the name of the note for t, including the .md extension
+func (f *FilenameFormat) Format(t time.Time) string {
+	return t.Format(f.layout)
+}
+
+// Parse returns the date of the note with the given name, which may omit the
+// .md extension, and whether the name matched the format
+func (f *FilenameFormat) Parse(name string) (time.Time, bool) {
+	name = path.Clean(name)
+	if !strings.HasSuffix(name, ".md") {
+		name += ".md"
+	}
+
+	t, err := time.Parse(f.layout, name)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// Nested reports whether notes are stored in subdirectories
+func (f *FilenameFormat) Nested() bool {
+	return strings.Contains(f.layout, "/")
+}
+
+// String returns the Go time layout of the format
+func (f *FilenameFormat) String() string {
+	return f.layout
+}
+
+// strftimeDirectives maps strftime directives to Go time layout elements
+var strftimeDirectives = map[byte]string{
+	'Y': "2006",
+	'y': "06",
+	'm': "01",
+	'd': "02",
+	'e': "_2",
+	'j': "002",
+	'b': "Jan",
+	'h': "Jan",
+	'B': "January",
+	'a': "Mon",
+	'A': "Monday",
+	'%': "%",
+}
+
+// literalCheckTimes are formatted with converted strftime patterns to find
+// literal text read as layout elements. They differ from each other and from
+// the reference time of layouts in every element.
+var literalCheckTimes = []time.Time{
+	time.Date(2031, time.October, 27, 19, 48, 39, 0, time.UTC),
+	time.Date(2047, time.August, 13, 8, 17, 29, 0, time.FixedZone("XYZ", 5*60*60)),
+}
+
+// timeOfDayCheckTimes share a date, and differ from each other in every
+// element of the time of day and zone
+var timeOfDayCheckTimes = []time.Time{
+	time.Date(2031, time.October, 27, 0, 0, 0, 0, time.UTC),
+	time.Date(2031, time.October, 27, 19, 48, 39, 123456789, time.FixedZone("XYZ", 5*60*60)),
+}
+
+// layoutPart is literal text or an element of a layout
+type layoutPart struct {
+	text    string
+	literal bool
+}
+
+// strftimeToLayout converts a strftime pattern to a Go time layout. Go layouts
+// cannot escape literal text, so patterns with literal text that would be read
+// as part of the date, such as the 2 of notes-2%Y or the Jan of Jan-%d, are
+// rejected.
+func strftimeToLayout(format string) (string, error) {
+	var layout strings.Builder
+	var parts []layoutPart
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			layout.WriteByte(format[i])
+			parts = append(parts, layoutPart{text: format[i : i+1], literal: true})
+			continue
+		}
+		if i+1 >= len(format) {
+			return "", fmt.Errorf("invalid filename format %q: trailing %%", format)
+		}
+		i++
+		element, ok := strftimeDirectives[format[i]]
+		if !ok {
+			return "", fmt.Errorf("invalid filename format %q: unsupported directive %%%c", format, format[i])
+		}
+		layout.WriteString(element)
+		parts = append(parts, layoutPart{text: element, literal: element == "%"})
+	}
+
+	for _, t := range literalCheckTimes {
+		var want strings.Builder
+		for _, part := range parts {
+			if part.literal {
+				want.WriteString(part.text)
+			} else {
+				want.WriteString(t.Format(part.text))
+			}
+		}
+		if t.Format(layout.String()) != want.String() {
+			return "", fmt.Errorf("invalid filename format %q: literal text would be read as part of the date", format)
+		}
+	}
+	return layout.String(), nil
+}
@@ -0,0 +1,75 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFilenameFormat(t *testing.T) {
+	date := time.Date(2024, time.December, 12, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		format string
+		// name of the note for date, empty when the format is invalid
+		name string
+	}{
+		{format: "", name: "2024-12-12.md"},
+		{format: "2006/01/2006-01-02.md", name: "2024/12/2024-12-12.md"},
+		{format: "%Y-%m-%d", name: "2024-12-12.md"},
+		{format: "%Y/%m/%Y-%m-%d.md", name: "2024/12/2024-12-12.md"},
+		{format: "daily-%Y%m%d", name: "daily-20241212.md"},
+		{format: "notes/%Y/%B/%d", name: "notes/2024/December/12.md"},
+		{format: "%a-%Y-%m-%d", name: "Thu-2024-12-12.md"},
+		{format: "done%%-%Y-%m-%d", name: "done%-2024-12-12.md"},
+		{format: "log_%Y_%m_%d", name: "log_2024_12_12.md"},
+
+		// literal text read as part of the date
+		{format: "notes-2%Y/%m-%d"},
+		{format: "v1-%Y-%m-%d"},
+		{format: "%Y-%m-%d-01"},
+		{format: "Jan-%Y-%m-%d"},
+		{format: "Mon-%Y-%m-%d"},
+		{format: "%Y-%m-%d-PM"},
+		{format: "%Y-%m-%d-MST"},
+		{format: "%Y-%m-%d_2"},
+
+		// literal text of layouts read as a time of day or zone
+		{format: "notes-v4-2006-01-02"},
+		{format: "2006-01-02-15"},
+		{format: "2006-01-02-PM"},
+		{format: "2006-01-02-MST"},
+		{format: "Z07-2006-01-02"},
+		{format: "2006-01-02.000"},
+		{format: "daily-2006-01-02", name: "daily-2024-12-12.md"},
+		{format: "Monday-2006-01-02", name: "Thursday-2024-12-12.md"},
+
+		// invalid patterns
+		{format: "%Y-%m"},
+		{format: "%Y-%m-%d%"},
+		{format: "%Y-%m-%Q"},
+		{format: "../%Y-%m-%d"},
+		{format: "/notes/%Y-%m-%d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			format, err := ParseFilenameFormat(tt.format)
+			if tt.name == "" {
+				if err == nil {
+					t.Fatalf("ParseFilenameFormat(%q) = %s, want an error", tt.format, format)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := format.Format(date); got != tt.name {
+				t.Errorf("Format() = %q, want %q", got, tt.name)
+			}
+			if got, ok := format.Parse(tt.name); !ok || !got.Equal(date) {
+				t.Errorf("Parse(%q) = %s, %v, want %s", tt.name, got, ok, date)
+			}
+		})
+	}
+}
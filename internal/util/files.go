@@ -1,45 +1,180 @@
 package util
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
-	"regexp"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
 // GetMostRecentMdFileName returns the most recent markdown (.md) extension file 30 days of endTime
-func GetMostRecentMdFileName(dirPath string, endTime time.Time) (string, error) {
+// named after its date with format. The name is relative to dirPath.
+func GetMostRecentMdFileName(dirPath string, format *FilenameFormat, endTime time.Time) (string, error) {
 	thirtyDaysAgo := endTime.AddDate(0, 0, -30)
 
-	// Compile regex for YYYY-MM-DD.md format
-	pattern := regexp.MustCompile(`^\d{4}-\d{2}-\d{2}\.md$`)
-
 	var mostRecentFile string
 	var mostRecentDate time.Time
 
-	entries, err := os.ReadDir(dirPath)
+	files, err := listDatedMdFiles(dirPath, format)
 	if err != nil {
 		return "", err
 	}
 
-	for _, entry := range entries {
-		filename := entry.Name()
-		if !pattern.MatchString(filename) {
-			continue
+	for _, file := range files {
+		fileDate := file.date
+		if fileDate.After(thirtyDaysAgo) && fileDate.Before(endTime) || fileDate.Equal(endTime) {
+			if mostRecentFile == "" || fileDate.After(mostRecentDate) {
+				mostRecentFile = file.name
+				mostRecentDate = fileDate
+			}
+		}
+	}
+
+	return mostRecentFile, nil
+}
+
+// GetMdFileNamesInRange returns the markdown (.md) extension files named after
+// their date with format, dated from startTime to endTime inclusive, oldest
+// first. Names are relative to dirPath.
+func GetMdFileNamesInRange(dirPath string, format *FilenameFormat, startTime time.Time, endTime time.Time) ([]string, error) {
+	start := startTime.Format("2006-01-02")
+	end := endTime.Format("2006-01-02")
+
+	files, err := listDatedMdFiles(dirPath, format)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, file := range files {
+		day := file.date.Format("2006-01-02")
+		if day >= start && day <= end {
+			names = append(names, file.name)
 		}
+	}
+
+	return names, nil
+}
+
+// GetMdFileNames returns every markdown (.md) extension file named after its
+// date with format, oldest first. Names are relative to dirPath.
+func GetMdFileNames(dirPath string, format *FilenameFormat) ([]string, error) {
+	files, err := listDatedMdFiles(dirPath, format)
+	if err != nil {
+		return nil, err
+	}
 
-		// Parse date from filename (removing .md extension)
-		fileDate, err := time.Parse("2006-01-02", filename[:10])
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, file.name)
+	}
+	return names, nil
+}
+
+// datedFile is a note file along with the date parsed from its name
+type datedFile struct {
+	name string
+	date time.Time
+}
+
+// listDatedMdFiles returns the files within dirPath named after their date
+// with format, oldest first. A directory that does not exist yet holds no
+// files.
+func listDatedMdFiles(dirPath string, format *FilenameFormat) ([]datedFile, error) {
+	var files []datedFile
+
+	if _, err := os.Stat(dirPath); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if !format.Nested() {
+		entries, err := os.ReadDir(dirPath)
 		if err != nil {
-			continue
+			return nil, err
 		}
 
-		if fileDate.After(thirtyDaysAgo) && fileDate.Before(endTime) || fileDate.Equal(endTime) {
-			if mostRecentFile == "" || fileDate.After(mostRecentDate) {
-				mostRecentFile = filename
-				mostRecentDate = fileDate
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			if date, ok := format.Parse(entry.Name()); ok {
+				files = append(files, datedFile{name: entry.Name(), date: date})
+			}
+		}
+	} else {
+		err := filepath.WalkDir(dirPath, func(p string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
 			}
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+				return nil
+			}
+
+			name, err := filepath.Rel(dirPath, p)
+			if err != nil {
+				return err
+			}
+			name = filepath.ToSlash(name)
+
+			if date, ok := format.Parse(name); ok {
+				files = append(files, datedFile{name: name, date: date})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return mostRecentFile, nil
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].date.Before(files[j].date)
+	})
+
+	return files, nil
+}
+
+// ParseISOWeek returns the Monday starting an ISO 8601 week such as 2024-W50
+func ParseISOWeek(week string) (time.Time, error) {
+	var year, number int
+	if _, err := fmt.Sscanf(week, "%4d-W%2d", &year, &number); err != nil || number < 1 || number > 53 {
+		return time.Time{}, fmt.Errorf("invalid ISO week %q: expected YYYY-Www", week)
+	}
+
+	// January 4th is always within the first ISO week of the year
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	offset := (int(jan4.Weekday()) + 6) % 7
+	monday := jan4.AddDate(0, 0, -offset+(number-1)*7)
+
+	if y, w := monday.ISOWeek(); y != year || w != number {
+		return time.Time{}, fmt.Errorf("invalid ISO week %q: %d has no week %d", week, year, number)
+	}
+
+	return monday, nil
+}
+
+// ParseSince returns the date a relative range such as 7d, 2w, 3m or 1y before
+// now begins
+func ParseSince(since string, now time.Time) (time.Time, error) {
+	var count int
+	var unit string
+	if _, err := fmt.Sscanf(since, "%d%s", &count, &unit); err != nil || count < 0 {
+		return time.Time{}, fmt.Errorf("invalid relative range %q: expected a number followed by d, w, m or y", since)
+	}
+
+	switch unit {
+	case "d":
+		return now.AddDate(0, 0, -count), nil
+	case "w":
+		return now.AddDate(0, 0, -7*count), nil
+	case "m":
+		return now.AddDate(0, -count, 0), nil
+	case "y":
+		return now.AddDate(-count, 0, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid relative range %q: expected a number followed by d, w, m or y", since)
+	}
 }
@@ -0,0 +1,120 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rdark/standupnotes/internal/diff"
+)
+
+// FileWriter writes note content atomically, optionally keeping a backup of
+// the previous content, or only printing the changes it would make
+type FileWriter struct {
+	// DryRun prints a unified diff of each change to Out instead of writing it
+	DryRun bool
+	// Backup keeps a timestamped copy of each file before it is overwritten
+	Backup bool
+	// Out receives the diffs of a dry run
+	Out io.Writer
+
+	// pending holds the content of files written during a dry run
+	pending map[string][]byte
+}
+
+// NewFileWriter returns a FileWriter printing dry run diffs to out
+func NewFileWriter(dryRun bool, backup bool, out io.Writer) *FileWriter {
+	return &FileWriter{
+		DryRun:  dryRun,
+		Backup:  backup,
+		Out:     out,
+		pending: make(map[string][]byte),
+	}
+}
+
+// ReadFile returns the content of the file at path, including content written
+// to it during a dry run
+func (w *FileWriter) ReadFile(path string) ([]byte, error) {
+	if content, ok := w.pending[filepath.Clean(path)]; ok {
+		return content, nil
+	}
+	return os.ReadFile(path)
+}
+
+// WriteFile replaces the content of the file at path, creating it and its
+// directory if needed. The file is written to a temporary file which is then
+// renamed over it, so readers never see partial content. Existing files keep
+// their mode, perm is only used for new files.
+func (w *FileWriter) WriteFile(path string, content []byte, perm fs.FileMode) error {
+	path = filepath.Clean(path)
+
+	old, err := w.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	exists := err == nil
+
+	if w.DryRun {
+		oldName := path
+		if !exists {
+			oldName = os.DevNull
+		}
+		if _, err := io.WriteString(w.Out, diff.Unified(oldName, path, old, content)); err != nil {
+			return err
+		}
+		w.pending[path] = content
+		return nil
+	}
+
+	if exists {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		perm = info.Mode().Perm()
+	}
+
+	if w.Backup && exists {
+		backupPath := fmt.Sprintf("%s.%s.bak", path, time.Now().Format("20060102T150405"))
+		if err := writeFileAtomic(backupPath, old, perm); err != nil {
+			return fmt.Errorf("backup of %s: %w", path, err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, content, perm)
+}
+
+// writeFileAtomic writes content to a temporary file alongside path and
+// renames it over path
+func writeFileAtomic(path string, content []byte, perm fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// the temporary file no longer exists once renamed
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
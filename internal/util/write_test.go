@@ -0,0 +1,49 @@
+package util
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileWriterKeepsMode(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "2024-12-12.md")
+	if err := os.WriteFile(path, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// the mode is set explicitly as WriteFile is subject to the umask
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewFileWriter(false, true, io.Discard)
+	if err := w.WriteFile(path, []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	newPath := filepath.Join(dir, "new", "2024-12-13.md")
+	if err := w.WriteFile(newPath, []byte("new"), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, err := filepath.Glob(path + ".*.bak")
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("got backups %v, %v, want one backup", backups, err)
+	}
+	want := map[string]fs.FileMode{path: 0600, backups[0]: 0600, newPath: 0640}
+	for path, mode := range want {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != mode {
+			t.Errorf("%s has mode %v, want %v", filepath.Base(path), got, mode)
+		}
+	}
+
+	if content, err := os.ReadFile(path); err != nil || string(content) != "new" {
+		t.Errorf("got content %q, %v, want the new content", content, err)
+	}
+}